@@ -1,19 +1,26 @@
 package cli
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"github.com/rarimo/relayer-svc/pkg/bouncer"
+	"github.com/rarimo/relayer-svc/pkg/secret"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 
 	"github.com/rarimo/relayer-svc/internal/services"
 	"github.com/rarimo/relayer-svc/internal/services/api"
+	"github.com/rarimo/relayer-svc/internal/services/bridger"
 
 	"github.com/rarimo/relayer-svc/internal/services/relayer"
 
 	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/signer"
 
 	"github.com/alecthomas/kingpin"
 	"gitlab.com/distributed_lab/kit/kv"
@@ -33,9 +40,17 @@ func Run(args []string) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	cfg := config.New(kv.MustFromEnv())
+	getter := kv.MustFromEnv()
+	cfg := config.New(getter)
 	log := cfg.Log()
 
+	tracer := cfg.Tracing()
+	defer func() {
+		if err := tracer.Shutdown(context.Background()); err != nil {
+			log.WithError(err).Error("failed to shutdown the tracer")
+		}
+	}()
+
 	var wg sync.WaitGroup
 	run := func(f func(config.Config, context.Context)) {
 		wg.Add(1)
@@ -62,6 +77,24 @@ func Run(args []string) {
 	relayerCmd := runCmd.Command("relayer", "run relayer")
 	generateKeyCmd := runCmd.Command("generate-key", "run generate-key")
 
+	chainCmd := app.Command("chain", "manage the destination chains")
+	pauseChainCmd := chainCmd.Command("pause", "pause relaying to the chain")
+	pauseChainName := pauseChainCmd.Arg("chain", "name of the chain").Required().String()
+	resumeChainCmd := chainCmd.Command("resume", "resume relaying to the chain")
+	resumeChainName := resumeChainCmd.Arg("chain", "name of the chain").Required().String()
+	drainSignerCmd := chainCmd.Command("drain", "stop selecting the signer for the withdrawals to the chain")
+	drainSignerChain := drainSignerCmd.Arg("chain", "name of the chain").Required().String()
+	drainSignerAddress := drainSignerCmd.Arg("signer", "address of the signer").Required().String()
+	undrainSignerCmd := chainCmd.Command("undrain", "select the drained signer for the withdrawals to the chain again")
+	undrainSignerChain := undrainSignerCmd.Arg("chain", "name of the chain").Required().String()
+	undrainSignerAddress := undrainSignerCmd.Arg("signer", "address of the signer").Required().String()
+
+	keystoreCmd := app.Command("keystore", "manage the local encrypted keystore")
+	importKeyCmd := keystoreCmd.Command("import", "encrypt the private key read from stdin into the keystore")
+	importKeySet := importKeyCmd.Arg("set", "secret key the private key is for").
+		Required().Enum("evm", "solana", "near", "rarimo", "bouncer")
+	importKeyName := importKeyCmd.Arg("name", "chain of the evm key or account of the near key").String()
+
 	cmd, err := app.Parse(args[1:])
 	if err != nil {
 		log.WithError(err).Fatal("failed to parse arguments")
@@ -70,24 +103,98 @@ func Run(args []string) {
 	switch cmd {
 	case runAllCmd.FullCommand():
 		log.Info("starting all services")
+		mustHaveSigners(ctx, cfg)
 		run(api.Run)
 		run(services.RunScheduler)
 		run(relayer.Run)
+		run(relayer.RunTracker)
 		run(services.RunQueueCleaner)
+		run(services.RunBalanceMonitor)
+		run(services.RunEndpointChecker)
+		run(services.RunNetworksWatcher)
+		run(services.RunVaultWatcher)
+		run(services.RunMetrics)
 	case apiCmd.FullCommand():
 		log.Info("starting API")
 		run(api.Run)
 	case relayerCmd.FullCommand():
+		mustHaveSigners(ctx, cfg)
 		log.Info("starting relayer")
 		run(relayer.Run)
+		log.Info("starting tracker")
+		run(relayer.RunTracker)
 		log.Info("starting queue cleaner")
 		run(services.RunQueueCleaner)
+		log.Info("starting balance monitor")
+		run(services.RunBalanceMonitor)
+		log.Info("starting endpoint checker")
+		run(services.RunEndpointChecker)
+		log.Info("starting networks watcher")
+		run(services.RunNetworksWatcher)
+		log.Info("starting vault watcher")
+		run(services.RunVaultWatcher)
+		run(services.RunMetrics)
 	case generateKeyCmd.FullCommand():
 		run(func(cfg config.Config, _ context.Context) {
 			bouncer.GenerateJWT(cfg.Bouncer().Config(), cfg.Vault(), cfg.Log())
 		})
+	case pauseChainCmd.FullCommand():
+		run(func(cfg config.Config, ctx context.Context) {
+			mustBeRelayChain(cfg, *pauseChainName)
+			if err := cfg.Redis().PauseChain(ctx, *pauseChainName, redis.PauseReasonManual); err != nil {
+				panic(errors.Wrap(err, "failed to pause the chain"))
+			}
+			log.WithField("chain", *pauseChainName).Info("chain is paused")
+		})
+	case resumeChainCmd.FullCommand():
+		run(func(cfg config.Config, ctx context.Context) {
+			mustBeRelayChain(cfg, *resumeChainName)
+			if err := cfg.Redis().ResumeChain(ctx, *resumeChainName); err != nil {
+				panic(errors.Wrap(err, "failed to resume the chain"))
+			}
+			log.WithField("chain", *resumeChainName).Info("chain is resumed")
+		})
+	case drainSignerCmd.FullCommand():
+		run(func(cfg config.Config, ctx context.Context) {
+			mustBeRelayChain(cfg, *drainSignerChain)
+			address := mustBeChainSigner(ctx, cfg, *drainSignerChain, *drainSignerAddress)
+			if err := cfg.Redis().DrainSigner(ctx, *drainSignerChain, address, redis.DrainReasonManual); err != nil {
+				panic(errors.Wrap(err, "failed to drain the signer"))
+			}
+			log.WithFields(logan.F{"chain": *drainSignerChain, "signer": address}).Info("signer is drained")
+		})
+	case undrainSignerCmd.FullCommand():
+		run(func(cfg config.Config, ctx context.Context) {
+			mustBeRelayChain(cfg, *undrainSignerChain)
+			address := mustBeChainSigner(ctx, cfg, *undrainSignerChain, *undrainSignerAddress)
+			if err := cfg.Redis().UndrainSigner(ctx, *undrainSignerChain, address); err != nil {
+				panic(errors.Wrap(err, "failed to undrain the signer"))
+			}
+			log.WithFields(logan.F{"chain": *undrainSignerChain, "signer": address}).Info("signer is undrained")
+		})
+	case importKeyCmd.FullCommand():
+		run(func(cfg config.Config, _ context.Context) {
+			keystoreConfig, err := secret.KeystoreConfigFrom(getter)
+			if err != nil {
+				panic(errors.Wrap(err, "failed to figure out keystore"))
+			}
+			if keystoreConfig == nil {
+				panic(errors.New("keystore is not configured"))
+			}
+
+			key, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && strings.TrimSpace(key) == "" {
+				panic(errors.Wrap(err, "failed to read the private key"))
+			}
+
+			path, err := secret.ImportKey(*keystoreConfig, *importKeySet, *importKeyName, strings.TrimSpace(key))
+			if err != nil {
+				panic(errors.Wrap(err, "failed to import the key"))
+			}
+			log.WithFields(logan.F{"set": *importKeySet, "path": path}).Info("key is imported")
+		})
 	default:
-		log.Fatal("unknown command %s", cmd)
+		log.Fatalf("unknown command %s", cmd)
 	}
 
 	var gracefulStop = make(chan os.Signal, 1)
@@ -112,3 +219,32 @@ func Run(args []string) {
 		<-wgch
 	}
 }
+
+func mustBeRelayChain(cfg config.Config, chain string) {
+	if !cfg.Chains().Has(chain) {
+		panic(errors.New(fmt.Sprintf("unknown chain %s", chain)))
+	}
+}
+
+// mustBeChainSigner returns the key address of the signer of the chain, so a mistyped address is not stored
+// as a drain that does nothing
+func mustBeChainSigner(ctx context.Context, cfg config.Config, chain, address string) string {
+	family, ok := cfg.Chains().Family(chain)
+	if !ok {
+		panic(errors.New(fmt.Sprintf("unknown family of the chain %s", chain)))
+	}
+
+	key, err := signer.Lookup(ctx, cfg.Signer(), chain, family, address)
+	if err != nil {
+		panic(errors.Wrap(err, fmt.Sprintf("failed to find the signer among the keys of the chain %s", chain)))
+	}
+
+	return key.Address
+}
+
+// mustHaveSigners stops the relaying instance that has no keys for some of its chains before it starts consuming
+func mustHaveSigners(ctx context.Context, cfg config.Config) {
+	if err := bridger.CheckSigners(ctx, cfg); err != nil {
+		cfg.Log().WithError(err).Fatal("relayer is not configured for its chains")
+	}
+}
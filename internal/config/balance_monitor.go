@@ -0,0 +1,98 @@
+package config
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const defaultBalanceMonitorInterval = time.Minute
+
+type BalanceMonitorer interface {
+	BalanceMonitor() *BalanceMonitorConfig
+}
+
+type BalanceMonitorConfig struct {
+	Disabled   bool               `fig:"disabled"`
+	Interval   time.Duration      `fig:"interval"`
+	Thresholds []BalanceThreshold `fig:"thresholds"`
+}
+
+// BalanceThreshold defines the signer balance limits for the chain in the smallest units of its native token
+type BalanceThreshold struct {
+	Chain string `fig:"chain,required"`
+	// Warning is the balance below which the monitor warns about the low funds
+	Warning *big.Int `fig:"warning"`
+	// Critical is the balance below which relaying to the chain is paused
+	Critical *big.Int `fig:"critical"`
+}
+
+type balanceMonitorer struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewBalanceMonitorer(getter kv.Getter) BalanceMonitorer {
+	return &balanceMonitorer{
+		getter: getter,
+	}
+}
+
+func (b *balanceMonitorer) BalanceMonitor() *BalanceMonitorConfig {
+	return b.once.Do(func() interface{} {
+		cfg := BalanceMonitorConfig{
+			Interval: defaultBalanceMonitorInterval,
+		}
+
+		err := figure.
+			Out(&cfg).
+			With(figure.BaseHooks, balanceThresholdsHook).
+			From(kv.MustGetStringMap(b.getter, "balance_monitor")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out balance monitor config"))
+		}
+
+		return &cfg
+	}).(*BalanceMonitorConfig)
+}
+
+// Threshold returns the balance limits configured for the chain
+func (c *BalanceMonitorConfig) Threshold(chain string) (BalanceThreshold, bool) {
+	for _, threshold := range c.Thresholds {
+		if threshold.Chain == chain {
+			return threshold, true
+		}
+	}
+
+	return BalanceThreshold{}, false
+}
+
+var balanceThresholdsHook = figure.Hooks{
+	"[]config.BalanceThreshold": func(value interface{}) (reflect.Value, error) {
+		rawSlice, err := cast.ToSliceE(value)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected slice of BalanceThreshold")
+		}
+
+		thresholds := make([]BalanceThreshold, len(rawSlice))
+		for idx, val := range rawSlice {
+			raw, err := cast.ToStringMapE(val)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected BalanceThreshold to be map[string]interface{}")
+			}
+
+			if err = figure.Out(&thresholds[idx]).With(figure.BaseHooks).From(raw).Please(); err != nil {
+				return reflect.Value{}, errors.Wrap(err, "malformed BalanceThreshold")
+			}
+		}
+
+		return reflect.ValueOf(thresholds), nil
+	},
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+type Chainser interface {
+	// Chains returns the registry of the destination chains the relayer is configured for
+	Chains() *Chains
+}
+
+// Chains maps the destination chains to their families. Every chain listed under `evm.chains`
+// belongs to the EVM family, the rest are declared in the `chains` section. The chains declared
+// without the family get it from the network type in the core params.
+type Chains struct {
+	names    []string
+	families map[string]types.ChainFamily
+	networks *Networks
+	// remote are the chains served by the other relayer instances
+	remote map[string]struct{}
+}
+
+type ChainDeclaration struct {
+	Name   string `fig:"name,required"`
+	Family string `fig:"family"`
+}
+
+type chainser struct {
+	getter   kv.Getter
+	evm      EVMer
+	networks Networkser
+	once     comfig.Once
+}
+
+func NewChainser(getter kv.Getter, evm EVMer, networks Networkser) Chainser {
+	return &chainser{
+		getter:   getter,
+		evm:      evm,
+		networks: networks,
+	}
+}
+
+var defaultChainDeclarations = []ChainDeclaration{
+	{Name: types.Solana, Family: string(types.FamilySolana)},
+	{Name: types.Near, Family: string(types.FamilyNear)},
+	{Name: types.Rarimo, Family: string(types.FamilyRarimo)},
+}
+
+func (c *chainser) Chains() *Chains {
+	return c.once.Do(func() interface{} {
+		cfg := struct {
+			List   []ChainDeclaration `fig:"list"`
+			Remote []string           `fig:"remote"`
+		}{
+			List: defaultChainDeclarations,
+		}
+
+		err := figure.
+			Out(&cfg).
+			With(figure.BaseHooks, chainDeclarationsHook).
+			From(kv.MustGetStringMap(c.getter, "chains")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out chains config"))
+		}
+
+		chains := Chains{
+			families: make(map[string]types.ChainFamily),
+			networks: c.networks.Networks(),
+		}
+		for _, declaration := range cfg.List {
+			if err := chains.add(declaration); err != nil {
+				panic(errors.Wrap(err, "invalid chain declaration"))
+			}
+		}
+
+		evm := c.evm.EVM()
+		for i := range evm.Chains {
+			if err := chains.add(ChainDeclaration{Name: evm.Chains[i].Name, Family: string(types.FamilyEVM)}); err != nil {
+				panic(errors.Wrap(err, "invalid evm chain"))
+			}
+		}
+
+		chains.remote = make(map[string]struct{}, len(cfg.Remote))
+		for _, name := range cfg.Remote {
+			if chains.Has(name) {
+				panic(errors.From(errors.New("chain is both served and remote"), logan.F{"chain": name}))
+			}
+			chains.remote[name] = struct{}{}
+		}
+
+		return &chains
+	}).(*Chains)
+}
+
+func (c *Chains) add(declaration ChainDeclaration) error {
+	fields := logan.F{"chain": declaration.Name, "family": declaration.Family}
+
+	if _, ok := c.families[declaration.Name]; ok {
+		return errors.From(errors.New("chain is declared twice"), fields)
+	}
+
+	family := types.ChainFamily(declaration.Family)
+	if family == "" {
+		// resolved from the core params on use
+		c.names = append(c.names, declaration.Name)
+		c.families[declaration.Name] = family
+		return nil
+	}
+
+	if !family.IsValid() {
+		return errors.From(errors.New("unknown chain family"), fields)
+	}
+
+	// solana, near and rarimo configs describe the single chain each
+	if family != types.FamilyEVM && len(c.ByFamily(family)) > 0 {
+		return errors.From(errors.New("only one chain of the family is supported"), fields)
+	}
+
+	c.names = append(c.names, declaration.Name)
+	c.families[declaration.Name] = family
+
+	return nil
+}
+
+// Names returns the names of all the configured chains
+func (c *Chains) Names() []string {
+	return c.names
+}
+
+// Has tells whether the chain is configured
+func (c *Chains) Has(chain string) bool {
+	_, ok := c.families[chain]
+	return ok
+}
+
+// IsRemote tells whether the chain is served by the other relayer instance, so its tasks are forwarded to
+// the chain queue instead of being dead-lettered as the ones of the unknown chains
+func (c *Chains) IsRemote(chain string) bool {
+	_, ok := c.remote[chain]
+	return ok
+}
+
+// Routable tells whether any relayer instance consumes the relay queue of the chain
+func (c *Chains) Routable(chain string) bool {
+	return c.Has(chain) || c.IsRemote(chain)
+}
+
+// Family returns the family of the chain, false is returned if the chain is not configured or
+// its family is not declared and is not discovered from the core yet
+func (c *Chains) Family(chain string) (types.ChainFamily, bool) {
+	family, ok := c.families[chain]
+	if !ok {
+		return "", false
+	}
+	if family != "" {
+		return family, true
+	}
+
+	network, ok := c.networks.Get(chain)
+	if !ok {
+		return "", false
+	}
+
+	return network.Family()
+}
+
+// DeclaredFamily returns the family of the chain set in the config, it is empty for the discovered ones
+func (c *Chains) DeclaredFamily(chain string) types.ChainFamily {
+	return c.families[chain]
+}
+
+// ByFamily returns the names of the configured chains of the family
+func (c *Chains) ByFamily(family types.ChainFamily) []string {
+	names := make([]string, 0)
+	for _, name := range c.names {
+		if resolved, ok := c.Family(name); ok && resolved == family {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+var chainDeclarationsHook = figure.Hooks{
+	"[]config.ChainDeclaration": func(value interface{}) (reflect.Value, error) {
+		rawSlice, err := cast.ToSliceE(value)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected slice of ChainDeclaration")
+		}
+
+		declarations := make([]ChainDeclaration, len(rawSlice))
+		for idx, val := range rawSlice {
+			raw, err := cast.ToStringMapE(val)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected ChainDeclaration to be map[string]interface{}")
+			}
+
+			if err = figure.Out(&declarations[idx]).With(figure.BaseHooks).From(raw).Please(); err != nil {
+				return reflect.Value{}, errors.Wrap(err, "malformed ChainDeclaration")
+			}
+		}
+
+		return reflect.ValueOf(declarations), nil
+	},
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+type staticNetworkser struct{}
+
+func (staticNetworkser) Networks() *Networks {
+	return &Networks{networks: make(map[string]Network)}
+}
+
+func TestChainsRoutable(t *testing.T) {
+	getter := kv.GetterFunc(func(key string) (map[string]interface{}, error) {
+		switch key {
+		case "chains":
+			return map[string]interface{}{
+				"list":   []interface{}{map[string]interface{}{"name": "Solana", "family": "solana"}},
+				"remote": []interface{}{"Near"},
+			}, nil
+		case "evm":
+			return map[string]interface{}{"chains": []interface{}{evmChainConfig(nil)}}, nil
+		default:
+			return nil, nil
+		}
+	})
+	chains := NewChainser(getter, NewEVMer(getter), staticNetworkser{}).Chains()
+
+	cases := []struct {
+		chain    string
+		served   bool
+		remote   bool
+		routable bool
+	}{
+		{chain: "Solana", served: true, routable: true},
+		{chain: "Goerli", served: true, routable: true},
+		{chain: "Near", remote: true, routable: true},
+		{chain: "Rarimo"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.chain, func(t *testing.T) {
+			if got := chains.Has(tc.chain); got != tc.served {
+				t.Errorf("Has() = %v, want %v", got, tc.served)
+			}
+			if got := chains.IsRemote(tc.chain); got != tc.remote {
+				t.Errorf("IsRemote() = %v, want %v", got, tc.remote)
+			}
+			if got := chains.Routable(tc.chain); got != tc.routable {
+				t.Errorf("Routable() = %v, want %v", got, tc.routable)
+			}
+		})
+	}
+}
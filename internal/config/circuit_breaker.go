@@ -0,0 +1,61 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type CircuitBreakerer interface {
+	CircuitBreaker() *CircuitBreakerConfig
+}
+
+type CircuitBreakerConfig struct {
+	Disabled bool `fig:"disabled"`
+	// MinRequests is the number of requests in the interval required before the failure ratio is evaluated
+	MinRequests uint32 `fig:"min_requests"`
+	// FailureRatio opens the breaker when the share of the failed requests in the interval reaches it
+	FailureRatio float64 `fig:"failure_ratio"`
+	// Interval is the period the failures are counted in while the breaker is closed
+	Interval time.Duration `fig:"interval"`
+	// OpenTimeout is the period after which the open breaker lets the probe requests through
+	OpenTimeout time.Duration `fig:"open_timeout"`
+	// HalfOpenRequests is the number of successful probe requests required to close the breaker
+	HalfOpenRequests uint32 `fig:"half_open_requests"`
+}
+
+type circuitBreakerer struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewCircuitBreakerer(getter kv.Getter) CircuitBreakerer {
+	return &circuitBreakerer{
+		getter: getter,
+	}
+}
+
+func (c *circuitBreakerer) CircuitBreaker() *CircuitBreakerConfig {
+	return c.once.Do(func() interface{} {
+		cfg := CircuitBreakerConfig{
+			MinRequests:      10,
+			FailureRatio:     0.5,
+			Interval:         time.Minute,
+			OpenTimeout:      30 * time.Second,
+			HalfOpenRequests: 1,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, "circuit_breaker")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out circuit breaker config"))
+		}
+
+		return &cfg
+	}).(*CircuitBreakerConfig)
+}
@@ -6,10 +6,15 @@ import (
 	"gitlab.com/distributed_lab/figure/v3"
 	"gitlab.com/distributed_lab/kit/comfig"
 	"gitlab.com/distributed_lab/kit/kv"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
 )
 
+const cosmosResolverScheme = "cosmos"
+
 type Cosmoser interface {
 	Cosmos() *grpc.ClientConn
 }
@@ -28,17 +33,36 @@ func NewCosmoser(getter kv.Getter) Cosmoser {
 func (c *cosmoser) Cosmos() *grpc.ClientConn {
 	return c.once.Do(func() interface{} {
 		var config struct {
-			Addr string `fig:"addr"`
+			// Addr is the ordered list of the core gRPC endpoints, the connection switches to the
+			// next one when the current endpoint becomes unreachable
+			Addr []string `fig:"addr"`
 		}
 
 		if err := figure.Out(&config).From(kv.MustGetStringMap(c.getter, "cosmos")).Please(); err != nil {
 			panic(err)
 		}
 
-		con, err := grpc.Dial(config.Addr, grpc.WithInsecure(), grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:    10 * time.Second, // wait time before ping if no activity
-			Timeout: 20 * time.Second, // ping timeout
-		}))
+		addresses := make([]resolver.Address, len(config.Addr))
+		for i, addr := range config.Addr {
+			addresses[i] = resolver.Address{Addr: addr}
+		}
+
+		endpoints := manual.NewBuilderWithScheme(cosmosResolverScheme)
+		endpoints.InitialState(resolver.State{Addresses: addresses})
+
+		con, err := grpc.Dial(
+			cosmosResolverScheme+":///core",
+			grpc.WithResolvers(endpoints),
+			// pick_first tries the addresses in the configured order
+			grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"pick_first":{}}]}`),
+			grpc.WithInsecure(),
+			grpc.WithKeepaliveParams(keepalive.ClientParameters{
+				Time:    10 * time.Second, // wait time before ping if no activity
+				Timeout: 20 * time.Second, // ping timeout
+			}),
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+		)
 		if err != nil {
 			panic(err)
 		}
@@ -4,16 +4,22 @@ import (
 	"context"
 	"math/big"
 	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
 
-	avalanche "github.com/ava-labs/subnet-evm/ethclient"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/spf13/cast"
 	"gitlab.com/distributed_lab/figure/v3"
 	"gitlab.com/distributed_lab/kit/comfig"
 	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/failover"
 )
 
 type EVMer interface {
@@ -30,14 +36,111 @@ type EVM struct {
 }
 
 type EVMChain struct {
-	Name                string            `fig:"name,required"`
-	BridgeFacadeAddress common.Address    `fig:"bridge_facade_address,required"`
-	BridgeAddress       common.Address    `fig:"bridge_address,required"`
-	RPC                 *ethclient.Client `fig:"-"`
-	RPCURL              string            `fig:"rpc,required"`
-	ChainID             *big.Int          `fig:"-"`
+	Name                string         `fig:"name,required"`
+	BridgeFacadeAddress common.Address `fig:"bridge_facade_address,required"`
+	// BridgeAddress is optional, the one from the core params takes precedence
+	BridgeAddress common.Address `fig:"bridge_address"`
+	// RPC serves the requests with the first healthy endpoint of RPCURLs
+	RPC     *failover.Pool[*ethclient.Client] `fig:"-"`
+	RPCURLs []string                          `fig:"rpc,required"`
+	// Quorum is the number of endpoints that must agree on the read call result, values
+	// less than two disable the comparison
+	Quorum int `fig:"quorum"`
+	// ChainID is optional, if it is not set the chain ID is requested from the endpoints on the first use
+	ChainID *big.Int `fig:"chain_id"`
+	// Confirmations is the number of blocks, including the one with the transaction, the withdrawal
+	// must be buried under before the transfer is considered relayed
+	Confirmations uint64         `fig:"confirmations"`
+	Gas           EVMGas         `fig:"gas"`
+	Fees          EVMFees        `fig:"fees"`
+	Replacement   EVMReplacement `fig:"replacement"`
+
+	// resolvedChainID is the chain ID requested from the endpoints when ChainID is not set
+	resolvedChainID atomic.Pointer[big.Int] `fig:"-"`
+	// resolvedFeeMode is the fee mode detected from the latest block in the auto mode
+	resolvedFeeMode atomic.Pointer[string] `fig:"-"`
+}
+
+// EVMGas bounds the estimated gas limit of the withdrawal transactions
+type EVMGas struct {
+	// Multiplier is applied to the estimated gas to leave a margin for the state changes between
+	// the estimation and the execution
+	Multiplier float64 `fig:"multiplier"`
+	MinLimit   uint64  `fig:"min_limit"`
+	// MaxLimit of zero means the limit is not capped
+	MaxLimit   uint64            `fig:"max_limit"`
+	TokenTypes []EVMGasTokenType `fig:"token_types"`
+}
+
+// EVMGasTokenType overrides the chain gas limit bounds for the token type
+type EVMGasTokenType struct {
+	// TokenType is the token manager type name, e.g. NATIVE, ERC20, ERC721 or ERC1155
+	TokenType string `fig:"token_type,required"`
+	MinLimit  uint64 `fig:"min_limit"`
+	MaxLimit  uint64 `fig:"max_limit"`
+}
+
+const defaultEVMGasMultiplier = 1.2
+
+// Limit applies the multiplier and the bounds of the token type to the estimated gas
+func (g EVMGas) Limit(tokenType string, estimated uint64) uint64 {
+	multiplier := g.Multiplier
+	if multiplier == 0 {
+		multiplier = defaultEVMGasMultiplier
+	}
+
+	minLimit, maxLimit := g.MinLimit, g.MaxLimit
+	for _, bounds := range g.TokenTypes {
+		if bounds.TokenType == tokenType {
+			minLimit, maxLimit = bounds.MinLimit, bounds.MaxLimit
+			break
+		}
+	}
+
+	limit := uint64(float64(estimated) * multiplier)
+	if limit < minLimit {
+		limit = minLimit
+	}
+	if maxLimit != 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return limit
+}
 
-	avalancheOnce comfig.Once
+const (
+	// EVMFeeModeDynamic sends the EIP-1559 transactions
+	EVMFeeModeDynamic = "eip1559"
+	// EVMFeeModeLegacy sends the legacy transactions for the chains without EIP-1559 support
+	EVMFeeModeLegacy = "legacy"
+	// EVMFeeModeAuto picks the dynamic mode if the latest block reports the base fee and the legacy one otherwise
+	EVMFeeModeAuto = "auto"
+)
+
+// EVMFees defines how the withdrawal transaction fees are priced
+type EVMFees struct {
+	Mode string `fig:"mode"`
+	// HistoryBlocks is the number of the latest blocks the eth_feeHistory is requested for
+	HistoryBlocks uint64 `fig:"history_blocks"`
+	// TipPercentile is the percentile of the priority fees paid in the block the tip is taken from
+	TipPercentile float64 `fig:"tip_percentile"`
+	// BaseFeeMultiplier is applied to the next block base fee to keep the fee cap valid while it grows
+	BaseFeeMultiplier float64  `fig:"base_fee_multiplier"`
+	MinTip            *big.Int `fig:"min_tip"`
+	// GasPriceMultiplier is applied to the suggested gas price in the legacy mode
+	GasPriceMultiplier float64 `fig:"gas_price_multiplier"`
+	// MaxFee caps the fee per gas, the withdrawal is deferred while the market requires more
+	MaxFee *big.Int `fig:"max_fee"`
+}
+
+// EVMReplacement defines how the transactions stuck in the mempool are re-sent with the bumped fees
+type EVMReplacement struct {
+	// Timeout is the period the transaction is awaited before it is replaced, zero waits without the limit
+	Timeout time.Duration `fig:"timeout"`
+	// FeeBump is applied to the fees of the replaced transaction, the nodes usually reject the bumps below 1.1
+	FeeBump float64 `fig:"fee_bump"`
+	// MaxReplacements is the number of replacements after which the transaction is considered stuck
+	MaxReplacements int `fig:"max_replacements"`
 }
 
 func NewEVMer(getter kv.Getter) EVMer {
@@ -63,21 +166,10 @@ func (e *evmer) EVM() *EVM {
 	}).(*EVM)
 }
 
-func (e *EVMChain) AvalancheRPC() avalanche.Client {
-	return e.avalancheOnce.Do(func() interface{} {
-		client, err := avalanche.Dial(e.RPCURL)
-		if err != nil {
-			panic(errors.Wrap(err, "failed to dial avalanche rpc"))
-		}
-
-		return client
-	}).(avalanche.Client)
-}
-
 func (e *EVM) GetChainByName(name string) (*EVMChain, bool) {
-	for _, chain := range e.Chains {
-		if chain.Name == name {
-			return &chain, true
+	for i := range e.Chains {
+		if e.Chains[i].Name == name {
+			return &e.Chains[i], true
 		}
 	}
 
@@ -108,23 +200,125 @@ func parseEVMChain(value interface{}) ([]EVMChain, error) {
 			return nil, errors.Wrap(err, "expected EVMChain to be map[string]interface{}")
 		}
 
-		var chain EVMChain
-		if err = figure.Out(&chain).With(figure.BaseHooks, figure.EthereumHooks).From(raw).Please(); err != nil {
+		chain := &chains[idx]
+		chain.Fees = EVMFees{
+			Mode:               EVMFeeModeAuto,
+			HistoryBlocks:      20,
+			TipPercentile:      50,
+			BaseFeeMultiplier:  2,
+			GasPriceMultiplier: 1.3,
+		}
+		chain.Replacement = EVMReplacement{
+			Timeout:         3 * time.Minute,
+			FeeBump:         1.125,
+			MaxReplacements: 3,
+		}
+		if err = figure.Out(chain).With(figure.BaseHooks, figure.EthereumHooks).From(raw).Please(); err != nil {
 			return nil, errors.Wrap(err, "malformed EVMChain")
 		}
-		chain.RPC, err = ethclient.Dial(chain.RPCURL)
+		if chain.Confirmations == 0 {
+			chain.Confirmations = 1
+		}
+		if chain.Fees.Mode != EVMFeeModeDynamic && chain.Fees.Mode != EVMFeeModeLegacy && chain.Fees.Mode != EVMFeeModeAuto {
+			return nil, errors.From(errors.New("unknown fee mode"), logan.F{"chain": chain.Name, "mode": chain.Fees.Mode})
+		}
+
+		chain.RPC, err = failover.NewPool(chain.Name, chain.RPCURLs, dialEVM, chain.checkRPC)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to dial eth rpc")
+			return nil, errors.Wrap(err, "failed to create eth rpc pool")
 		}
+	}
+
+	return chains, nil
+}
 
-		cID, err := chain.RPC.ChainID(context.TODO())
+// GetChainID returns the chain ID without dialing the endpoints while the config is parsed, so
+// the unavailable chain does not prevent the relayer from starting
+func (e *EVMChain) GetChainID(ctx context.Context) (*big.Int, error) {
+	if chainID := e.knownChainID(); chainID != nil {
+		return chainID, nil
+	}
+
+	rawChainID, err := failover.Quorum(ctx, e.RPC, e.Quorum, func(ctx context.Context, client *ethclient.Client) (string, error) {
+		chainID, err := client.ChainID(ctx)
 		if err != nil {
-			panic(errors.Wrap(err, "failed to get chain id"))
+			return "", err
 		}
-		chain.ChainID = cID
 
-		chains[idx] = chain
+		return chainID.String(), nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get chain id", logan.F{"chain": e.Name})
 	}
 
-	return chains, nil
+	chainID, _ := new(big.Int).SetString(rawChainID, 10)
+	e.resolvedChainID.Store(chainID)
+
+	return chainID, nil
+}
+
+func (e *EVMChain) knownChainID() *big.Int {
+	if e.ChainID != nil {
+		return e.ChainID
+	}
+
+	return e.resolvedChainID.Load()
+}
+
+// FeeMode returns the fee mode of the chain, the auto one is resolved once from the latest block header,
+// as the chains without EIP-1559 support do not report the base fee
+func (e *EVMChain) FeeMode(ctx context.Context, client *ethclient.Client) (string, error) {
+	if e.Fees.Mode != EVMFeeModeAuto {
+		return e.Fees.Mode, nil
+	}
+	if mode := e.resolvedFeeMode.Load(); mode != nil {
+		return *mode, nil
+	}
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the latest header", logan.F{"chain": e.Name})
+	}
+
+	mode := EVMFeeModeLegacy
+	if header.BaseFee != nil {
+		mode = EVMFeeModeDynamic
+	}
+	e.resolvedFeeMode.Store(&mode)
+
+	return mode, nil
+}
+
+// checkRPC makes sure the endpoint is reachable and serves the configured chain
+func (e *EVMChain) checkRPC(ctx context.Context, client *ethclient.Client) error {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get chain id")
+	}
+
+	if expected := e.knownChainID(); expected != nil && chainID.Cmp(expected) != 0 {
+		return errors.From(errors.New("endpoint serves another chain"), logan.F{
+			"expected": expected.String(),
+			"actual":   chainID.String(),
+		})
+	}
+
+	if _, err = client.BlockNumber(ctx); err != nil {
+		return errors.Wrap(err, "failed to get block number")
+	}
+
+	return nil
+}
+
+func dialEVM(url string) (*ethclient.Client, error) {
+	if !strings.HasPrefix(url, "http") {
+		return ethclient.Dial(url)
+	}
+
+	client, err := rpc.DialHTTPWithClient(url, tracedHTTPClient())
+	if err != nil {
+		return nil, err
+	}
+
+	return ethclient.NewClient(client), nil
 }
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+func TestEVMChainID(t *testing.T) {
+	cases := []struct {
+		name  string
+		chain map[string]interface{}
+		want  *big.Int
+	}{
+		{
+			name:  "configured chain id",
+			chain: evmChainConfig(map[string]interface{}{"chain_id": 5}),
+			want:  big.NewInt(5),
+		},
+		{
+			name:  "chain id requested from the endpoints",
+			chain: evmChainConfig(nil),
+			want:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			evm := NewEVMer(kv.GetterFunc(func(key string) (map[string]interface{}, error) {
+				return map[string]interface{}{"chains": []interface{}{tc.chain}}, nil
+			})).EVM()
+
+			chain, ok := evm.GetChainByName("Goerli")
+			if !ok {
+				t.Fatal("expected the chain to be parsed")
+			}
+
+			got := chain.knownChainID()
+			switch {
+			case tc.want == nil && got != nil:
+				t.Fatalf("expected no chain id, got %s", got)
+			case tc.want != nil && (got == nil || got.Cmp(tc.want) != 0):
+				t.Fatalf("expected chain id %s, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func evmChainConfig(extra map[string]interface{}) map[string]interface{} {
+	chain := map[string]interface{}{
+		"name":                  "Goerli",
+		"bridge_facade_address": "0x0000000000000000000000000000000000000001",
+		"rpc":                   []interface{}{"http://127.0.0.1:8545"},
+	}
+	for key, value := range extra {
+		chain[key] = value
+	}
+
+	return chain
+}
+
+func TestEVMFeeMode(t *testing.T) {
+	feesMode := func(mode string) map[string]interface{} {
+		return evmChainConfig(map[string]interface{}{"fees": map[string]interface{}{"mode": mode}})
+	}
+
+	cases := []struct {
+		name  string
+		chain map[string]interface{}
+		// baseFee is reported by the latest header of the node, nil for the chains without EIP-1559
+		baseFee *big.Int
+		want    string
+		wantErr bool
+	}{
+		{name: "auto by default", chain: evmChainConfig(nil), baseFee: big.NewInt(1), want: EVMFeeModeDynamic},
+		{name: "explicit legacy", chain: feesMode(EVMFeeModeLegacy), baseFee: big.NewInt(1), want: EVMFeeModeLegacy},
+		{name: "explicit eip1559", chain: feesMode(EVMFeeModeDynamic), want: EVMFeeModeDynamic},
+		{name: "auto with the base fee", chain: feesMode(EVMFeeModeAuto), baseFee: big.NewInt(1), want: EVMFeeModeDynamic},
+		{name: "auto without the base fee", chain: feesMode(EVMFeeModeAuto), want: EVMFeeModeLegacy},
+		{name: "invalid mode", chain: feesMode("eip4844"), wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			chains, err := parseEVMChain([]interface{}{tc.chain})
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected the fee mode to be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			server := httptest.NewServer(headerNode{baseFee: tc.baseFee})
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			got, err := chains[0].FeeMode(context.Background(), client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// headerNode answers the latest header requests with the header reporting the base fee
+type headerNode struct {
+	baseFee *big.Int
+}
+
+func (n headerNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch req.Method {
+	case "eth_getBlockByNumber":
+		resp["result"] = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), BaseFee: n.baseFee}
+	default:
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func TestEVMGasLimit(t *testing.T) {
+	gas := EVMGas{
+		Multiplier: 1.5,
+		MinLimit:   100_000,
+		MaxLimit:   400_000,
+		TokenTypes: []EVMGasTokenType{
+			{TokenType: "ERC1155", MinLimit: 200_000, MaxLimit: 800_000},
+			{TokenType: "ERC721", MinLimit: 150_000},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		gas       EVMGas
+		tokenType string
+		estimated uint64
+		want      uint64
+	}{
+		{
+			name:      "multiplier is applied",
+			gas:       gas,
+			tokenType: "NATIVE",
+			estimated: 200_000,
+			want:      300_000,
+		},
+		{
+			name:      "default multiplier",
+			gas:       EVMGas{},
+			tokenType: "NATIVE",
+			estimated: 100_000,
+			want:      120_000,
+		},
+		{
+			name:      "raised to the chain minimum",
+			gas:       gas,
+			tokenType: "ERC20",
+			estimated: 21_000,
+			want:      100_000,
+		},
+		{
+			name:      "capped by the chain maximum",
+			gas:       gas,
+			tokenType: "ERC20",
+			estimated: 1_000_000,
+			want:      400_000,
+		},
+		{
+			name:      "token type bounds override the chain ones",
+			gas:       gas,
+			tokenType: "ERC1155",
+			estimated: 500_000,
+			want:      750_000,
+		},
+		{
+			name:      "token type minimum",
+			gas:       gas,
+			tokenType: "ERC1155",
+			estimated: 21_000,
+			want:      200_000,
+		},
+		{
+			name:      "token type without the maximum is not capped",
+			gas:       gas,
+			tokenType: "ERC721",
+			estimated: 1_000_000,
+			want:      1_500_000,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.gas.Limit(tc.tokenType, tc.estimated); got != tc.want {
+				t.Fatalf("got limit %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
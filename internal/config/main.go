@@ -26,6 +26,16 @@ type Config interface {
 	Nearer
 	Schedulerer
 	Rarimoer
+	Tracinger
+	Metricser
+	BalanceMonitorer
+	CircuitBreakerer
+	Chainser
+	Networkser
+	Noncer
+	Trackerer
+	SignerPooler
+	Signerer
 }
 
 type config struct {
@@ -44,25 +54,50 @@ type config struct {
 	Nearer
 	Schedulerer
 	Rarimoer
+	Tracinger
+	Metricser
+	BalanceMonitorer
+	CircuitBreakerer
+	Chainser
+	Networkser
+	Noncer
+	Trackerer
+	SignerPooler
+	Signerer
 }
 
 func New(getter kv.Getter) Config {
 	logger := comfig.NewLogger(getter, comfig.LoggerOpts{})
+	evmer := NewEVMer(getter)
+	cosmoser := NewCosmoser(getter)
+	networkser := NewNetworkser(getter, cosmoser)
+	rediserer := redis.NewRediserer(getter, logger.Log())
+	vaulter := secret.NewVaulter(getter, logger.Log())
 	return &config{
-		Logger:       logger,
-		getter:       getter,
-		Copuser:      copus.NewCopuser(getter),
-		Listenerer:   comfig.NewListenerer(getter),
-		Rediserer:    redis.NewRediserer(getter, logger.Log()),
-		Bouncerer:    bouncer.NewBouncerer(getter),
-		Horizoner:    horizon.NewHorizoner(getter),
-		Tenderminter: NewTenderminter(getter),
-		Cosmoser:     NewCosmoser(getter),
-		EVMer:        NewEVMer(getter),
-		Solaner:      NewSolaner(getter),
-		Nearer:       NewNearer(getter),
-		Schedulerer:  NewSchedulerer(getter),
-		Vaulter:      secret.NewVaulter(getter, logger.Log()),
-		Rarimoer:     NewRarimoer(getter),
+		Logger:           logger,
+		getter:           getter,
+		Copuser:          copus.NewCopuser(getter),
+		Listenerer:       comfig.NewListenerer(getter),
+		Rediserer:        rediserer,
+		Bouncerer:        bouncer.NewBouncerer(getter),
+		Horizoner:        horizon.NewHorizoner(getter),
+		Tenderminter:     NewTenderminter(getter),
+		Cosmoser:         cosmoser,
+		EVMer:            evmer,
+		Solaner:          NewSolaner(getter),
+		Nearer:           NewNearer(getter),
+		Schedulerer:      NewSchedulerer(getter),
+		Vaulter:          vaulter,
+		Rarimoer:         NewRarimoer(getter),
+		Tracinger:        NewTracinger(getter),
+		Metricser:        NewMetricser(getter),
+		BalanceMonitorer: NewBalanceMonitorer(getter),
+		CircuitBreakerer: NewCircuitBreakerer(getter),
+		Chainser:         NewChainser(getter, evmer, networkser),
+		Networkser:       networkser,
+		Noncer:           NewNoncer(getter, rediserer),
+		Trackerer:        NewTrackerer(getter),
+		SignerPooler:     NewSignerPooler(getter, rediserer),
+		Signerer:         NewSignerer(getter, vaulter),
 	}
 }
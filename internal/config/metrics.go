@@ -0,0 +1,44 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type Metricser interface {
+	Metrics() *MetricsConfig
+}
+
+type MetricsConfig struct {
+	// Addr is the address to serve prometheus metrics on, metrics are not served if empty
+	Addr string `fig:"addr"`
+}
+
+type metricser struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewMetricser(getter kv.Getter) Metricser {
+	return &metricser{
+		getter: getter,
+	}
+}
+
+func (m *metricser) Metrics() *MetricsConfig {
+	return m.once.Do(func() interface{} {
+		var cfg MetricsConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(m.getter, "metrics")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out metrics config"))
+		}
+
+		return &cfg
+	}).(*MetricsConfig)
+}
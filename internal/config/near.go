@@ -1,13 +1,19 @@
 package config
 
 import (
+	"context"
+	"net/http"
+	"reflect"
+	"unsafe"
+
 	"github.com/rarimo/near-go/common"
 	"github.com/rarimo/near-go/nearclient"
-	"github.com/rarimo/near-go/nearprovider"
 	"gitlab.com/distributed_lab/figure/v3"
 	"gitlab.com/distributed_lab/kit/comfig"
 	"gitlab.com/distributed_lab/kit/kv"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/failover"
 )
 
 type nearer struct {
@@ -26,8 +32,10 @@ func NewNearer(getter kv.Getter) Nearer {
 }
 
 type Near struct {
-	RPC           *nearclient.Client `fig:"rpc"`
-	BridgeAddress common.AccountID   `fig:"bridge_address,required"`
+	RPC     *failover.Pool[*nearclient.Client] `fig:"-"`
+	RPCURLs []string                           `fig:"rpc,required"`
+	// BridgeAddress is optional, the one from the core params takes precedence
+	BridgeAddress common.AccountID `fig:"bridge_address"`
 }
 
 func (n *nearer) Near() *Near {
@@ -36,13 +44,53 @@ func (n *nearer) Near() *Near {
 
 		err := figure.
 			Out(&cfg).
-			With(nearprovider.NearHooks, figure.BaseHooks).
+			With(figure.BaseHooks).
 			From(kv.MustGetStringMap(n.getter, "near")).
 			Please()
 		if err != nil {
 			panic(errors.Wrap(err, "failed to figure config"))
 		}
 
+		cfg.RPC, err = failover.NewPool("near_rpc", cfg.RPCURLs, dialNear, checkNearRPC)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to create near rpc pool"))
+		}
+
 		return &cfg
 	}).(*Near)
 }
+
+// dialNear creates the NEAR client sending the requests with the traced HTTP client
+func dialNear(url string) (*nearclient.Client, error) {
+	client, err := nearclient.New(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = setNearHTTPClient(client, tracedHTTPClient()); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// setNearHTTPClient replaces the HTTP client the NEAR client creates for itself, as it does not accept one.
+// Only the client of the connection is replaced, the default transport shared with the rest of the process is
+// left intact.
+func setNearHTTPClient(client *nearclient.Client, httpClient *http.Client) error {
+	field := reflect.ValueOf(client.RPCClient).Elem().FieldByName("client")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(httpClient) {
+		return errors.New("unexpected NEAR RPC client layout, the HTTP client can not be set")
+	}
+
+	*(**http.Client)(unsafe.Pointer(field.UnsafeAddr())) = httpClient
+	return nil
+}
+
+func checkNearRPC(ctx context.Context, client *nearclient.Client) error {
+	if _, err := client.BlockDetails(ctx, nearclient.FinalityFinal()); err != nil {
+		return errors.Wrap(err, "failed to get the final block")
+	}
+
+	return nil
+}
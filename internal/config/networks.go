@@ -0,0 +1,151 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tokenmanager "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+const defaultNetworksRefreshInterval = time.Minute
+
+type Networkser interface {
+	// Networks returns the destination chains params discovered from the core
+	Networks() *Networks
+}
+
+type NetworksConfig struct {
+	// Disabled turns off the discovery, so only the locally configured params are used
+	Disabled        bool          `fig:"disabled"`
+	RefreshInterval time.Duration `fig:"refresh_interval"`
+}
+
+// Network is the destination chain as it is registered in the core token manager params
+type Network struct {
+	Name string
+	Type tokenmanager.NetworkType
+	// Contract is the bridge contract address for EVM, the program ID for Solana and the bridge account for Near
+	Contract string
+	// Admin is the bridge admin, it is set for Solana only
+	Admin string
+}
+
+// Family returns the chain family matching the network type
+func (n Network) Family() (types.ChainFamily, bool) {
+	switch n.Type {
+	case tokenmanager.NetworkType_EVM:
+		return types.FamilyEVM, true
+	case tokenmanager.NetworkType_Solana:
+		return types.FamilySolana, true
+	case tokenmanager.NetworkType_Near:
+		return types.FamilyNear, true
+	case tokenmanager.NetworkType_Rarimo:
+		return types.FamilyRarimo, true
+	default:
+		return "", false
+	}
+}
+
+// Networks keeps the last fetched core params, it stays empty until the first Refresh
+type Networks struct {
+	NetworksConfig
+
+	tokenmanager tokenmanager.QueryClient
+	mu           sync.RWMutex
+	networks     map[string]Network
+}
+
+type networkser struct {
+	getter kv.Getter
+	cosmos Cosmoser
+	once   comfig.Once
+}
+
+func NewNetworkser(getter kv.Getter, cosmos Cosmoser) Networkser {
+	return &networkser{
+		getter: getter,
+		cosmos: cosmos,
+	}
+}
+
+func (n *networkser) Networks() *Networks {
+	return n.once.Do(func() interface{} {
+		cfg := NetworksConfig{
+			RefreshInterval: defaultNetworksRefreshInterval,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(n.getter, "networks")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out networks config"))
+		}
+
+		return &Networks{
+			NetworksConfig: cfg,
+			tokenmanager:   tokenmanager.NewQueryClient(n.cosmos.Cosmos()),
+			networks:       make(map[string]Network),
+		}
+	}).(*Networks)
+}
+
+// Refresh fetches the core params and returns the names of the networks that changed since the last refresh
+func (n *Networks) Refresh(ctx context.Context) ([]string, error) {
+	if n.Disabled {
+		return nil, nil
+	}
+
+	resp, err := n.tokenmanager.Params(ctx, new(tokenmanager.QueryParamsRequest))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the network params")
+	}
+
+	networks := make(map[string]Network, len(resp.Params.Networks))
+	for _, network := range resp.Params.Networks {
+		discovered := Network{Name: network.Name, Type: network.Type}
+		if params := network.GetBridgeParams(); params != nil {
+			discovered.Contract = params.Contract
+			discovered.Admin = params.Admin
+		}
+
+		networks[network.Name] = discovered
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	changed := make([]string, 0)
+	for name, network := range networks {
+		if previous, ok := n.networks[name]; !ok || previous != network {
+			changed = append(changed, name)
+		}
+	}
+	for name := range n.networks {
+		if _, ok := networks[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	n.networks = networks
+
+	return changed, nil
+}
+
+// Get returns the network params of the chain if the core has them
+func (n *Networks) Get(chain string) (Network, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	network, ok := n.networks[chain]
+	return network, ok
+}
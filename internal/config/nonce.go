@@ -0,0 +1,65 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/nonce"
+)
+
+type Noncer interface {
+	// Nonces returns the nonce allocator shared by the submissions to all the EVM chains, NEAR and Rarimo
+	Nonces() nonce.Allocator
+}
+
+type NonceConfig struct {
+	// Backend is either local for the single replica or redis for the replicas sharing the signers
+	Backend string `fig:"backend"`
+	// GapTimeout is the period after which the nonce unknown to the node is handed out again
+	GapTimeout time.Duration `fig:"gap_timeout"`
+}
+
+type noncer struct {
+	getter kv.Getter
+	redis  redis.Rediserer
+	once   comfig.Once
+}
+
+func NewNoncer(getter kv.Getter, redis redis.Rediserer) Noncer {
+	return &noncer{
+		getter: getter,
+		redis:  redis,
+	}
+}
+
+func (n *noncer) Nonces() nonce.Allocator {
+	return n.once.Do(func() interface{} {
+		cfg := NonceConfig{
+			Backend:    nonce.BackendLocal,
+			GapTimeout: nonce.DefaultGapTimeout,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(n.getter, "nonce")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out nonce config"))
+		}
+
+		switch cfg.Backend {
+		case nonce.BackendLocal:
+			return nonce.NewLocal(cfg.GapTimeout)
+		case nonce.BackendRedis:
+			return nonce.NewRedis(n.redis.Redis().Client(), cfg.GapTimeout)
+		default:
+			panic(errors.From(errors.New("unknown nonce backend"), logan.F{"backend": cfg.Backend}))
+		}
+	}).(nonce.Allocator)
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/signer"
+	"github.com/rarimo/relayer-svc/pkg/secret"
+)
+
+type Signerer interface {
+	// Signer returns the signer of the withdrawal transactions of all the chains
+	Signer() signer.Signer
+}
+
+type SignerConfig struct {
+	// Backend is either local or transit
+	Backend string                `fig:"backend"`
+	Transit *signer.TransitConfig `fig:"transit"`
+}
+
+type signerer struct {
+	getter kv.Getter
+	vault  secret.Vaulter
+	once   comfig.Once
+}
+
+func NewSignerer(getter kv.Getter, vault secret.Vaulter) Signerer {
+	return &signerer{
+		getter: getter,
+		vault:  vault,
+	}
+}
+
+func (s *signerer) Signer() signer.Signer {
+	return s.once.Do(func() interface{} {
+		cfg := SignerConfig{
+			Backend: signer.BackendLocal,
+		}
+
+		err := figure.
+			Out(&cfg).
+			With(figure.BaseHooks, transitHooks).
+			From(kv.MustGetStringMap(s.getter, "signer")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out signer config"))
+		}
+
+		switch cfg.Backend {
+		case signer.BackendLocal:
+			return signer.NewLocal(s.vault.Vault())
+		case signer.BackendTransit:
+			if cfg.Transit == nil {
+				panic(errors.New("transit signer config is missing"))
+			}
+
+			client := s.vault.Vault().Client()
+			if client == nil {
+				panic(errors.New("transit signer requires Vault, the local keystore has no Transit keys"))
+			}
+
+			if cfg.Transit.RefreshInterval == 0 {
+				cfg.Transit.RefreshInterval = s.vault.Vault().RefreshInterval()
+			}
+
+			return signer.NewTransit(client, *cfg.Transit)
+		default:
+			panic(errors.From(errors.New("unknown signer backend"), logan.F{"backend": cfg.Backend}))
+		}
+	}).(signer.Signer)
+}
+
+var transitHooks = figure.Hooks{
+	"*signer.TransitConfig": func(value interface{}) (reflect.Value, error) {
+		raw, err := cast.ToStringMapE(value)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected TransitConfig to be map[string]interface{}")
+		}
+
+		var cfg signer.TransitConfig
+		if err = figure.Out(&cfg).With(figure.BaseHooks, transitKeysHook).From(raw).Please(); err != nil {
+			return reflect.Value{}, errors.Wrap(err, "malformed TransitConfig")
+		}
+
+		return reflect.ValueOf(&cfg), nil
+	},
+}
+
+var transitKeysHook = figure.Hooks{
+	"[]signer.TransitKey": func(value interface{}) (reflect.Value, error) {
+		rawSlice, err := cast.ToSliceE(value)
+		if err != nil {
+			return reflect.Value{}, errors.Wrap(err, "expected slice of TransitKey")
+		}
+
+		keys := make([]signer.TransitKey, len(rawSlice))
+		for idx, val := range rawSlice {
+			raw, err := cast.ToStringMapE(val)
+			if err != nil {
+				return reflect.Value{}, errors.Wrap(err, "expected TransitKey to be map[string]interface{}")
+			}
+
+			if err = figure.Out(&keys[idx]).With(figure.BaseHooks).From(raw).Please(); err != nil {
+				return reflect.Value{}, errors.Wrap(err, "malformed TransitKey")
+			}
+		}
+
+		return reflect.ValueOf(keys), nil
+	},
+}
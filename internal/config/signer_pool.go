@@ -0,0 +1,57 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+)
+
+type SignerPooler interface {
+	// SignerPool returns the pool selecting the withdrawal signers of all the chains
+	SignerPool() *signerpool.Pool
+}
+
+type SignerPoolConfig struct {
+	// Strategy is either least_loaded or round_robin
+	Strategy string `fig:"strategy"`
+}
+
+type signerPooler struct {
+	getter kv.Getter
+	redis  redis.Rediserer
+	once   comfig.Once
+}
+
+func NewSignerPooler(getter kv.Getter, redis redis.Rediserer) SignerPooler {
+	return &signerPooler{
+		getter: getter,
+		redis:  redis,
+	}
+}
+
+func (s *signerPooler) SignerPool() *signerpool.Pool {
+	return s.once.Do(func() interface{} {
+		cfg := SignerPoolConfig{
+			Strategy: signerpool.StrategyLeastLoaded,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(s.getter, "signer_pool")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out signer pool config"))
+		}
+
+		if cfg.Strategy != signerpool.StrategyLeastLoaded && cfg.Strategy != signerpool.StrategyRoundRobin {
+			panic(errors.From(errors.New("unknown signer selection strategy"), logan.F{"strategy": cfg.Strategy}))
+		}
+
+		return signerpool.New(cfg.Strategy, s.redis.Redis())
+	}).(*signerpool.Pool)
+}
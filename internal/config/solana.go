@@ -7,7 +7,8 @@ import (
 	"github.com/mr-tron/base58"
 	"github.com/olegfomenko/solana-go"
 	"github.com/olegfomenko/solana-go/rpc"
-	"github.com/olegfomenko/solana-go/rpc/ws"
+	"github.com/olegfomenko/solana-go/rpc/jsonrpc"
+	"github.com/rarimo/relayer-svc/internal/failover"
 	"github.com/rarimo/relayer-svc/internal/utils"
 	"github.com/spf13/cast"
 	"gitlab.com/distributed_lab/figure/v3"
@@ -21,11 +22,83 @@ type Solaner interface {
 }
 
 type Solana struct {
-	RPC             *rpc.Client      `fig:"rpc,required"`
-	WS              *ws.Client       `fig:"ws,required"`
+	RPC     *failover.Pool[*rpc.Client] `fig:"-"`
+	RPCURLs []string                    `fig:"rpc,required"`
+	// Quorum is the number of RPC endpoints that must agree on the read call result, values
+	// less than two disable the comparison
+	Quorum          int              `fig:"quorum"`
 	BridgeAdmin     solana.PublicKey `fig:"-"`
 	BridgeAdminSeed [32]byte         `fig:"bridge_admin_seed,required"`
-	BridgeProgramID solana.PublicKey `fig:"bridge_program_id,required"`
+	// BridgeProgramID is optional, the one from the core params takes precedence
+	BridgeProgramID solana.PublicKey    `fig:"bridge_program_id"`
+	ComputeBudget   SolanaComputeBudget `fig:"compute_budget"`
+	LookupTable     SolanaLookupTable   `fig:"lookup_table"`
+}
+
+// SolanaLookupTable configures the address lookup table of the withdrawals, the account loaded from the table
+// takes one byte of the transaction instead of 32, leaving more room for the merkle path
+type SolanaLookupTable struct {
+	// Address of the existing table, the relayer creates its own one when it is omitted and the table is managed
+	Address solana.PublicKey `fig:"address"`
+	// Managed table is extended by the relayer with the accounts shared by the withdrawals
+	Managed bool `fig:"managed"`
+	// Authority is the signer key owning the managed table and paying for it
+	Authority solana.PublicKey `fig:"authority"`
+}
+
+// Enabled tells whether the withdrawals load the accounts from the lookup table
+func (t SolanaLookupTable) Enabled() bool {
+	return t.Managed || !t.Address.IsZero()
+}
+
+// maxSolanaComputeUnits is the compute unit limit of the transaction enforced by the cluster
+const maxSolanaComputeUnits = 1_400_000
+
+// SolanaComputeBudget defines the compute unit limit and price of the withdrawal transactions, so they are
+// prioritized by the leaders under congestion
+type SolanaComputeBudget struct {
+	// Disabled sends the withdrawals without the compute budget instructions
+	Disabled bool `fig:"disabled"`
+	// Multiplier is applied to the simulated compute units to leave a margin for the state changes between
+	// the simulation and the execution
+	Multiplier float64 `fig:"multiplier"`
+	MinLimit   uint32  `fig:"min_limit"`
+	MaxLimit   uint32  `fig:"max_limit"`
+	// FeePercentile is the percentile of the recent prioritization fees paid for the bridge accounts the unit
+	// price is taken from
+	FeePercentile float64 `fig:"fee_percentile"`
+	// MinUnitPrice and MaxUnitPrice bound the compute unit price in micro-lamports, the withdrawal is deferred
+	// while the market requires more than the max, zero max means the price is not capped
+	MinUnitPrice uint64 `fig:"min_unit_price"`
+	MaxUnitPrice uint64 `fig:"max_unit_price"`
+	// MaxPriorityFee caps the priority fee of the transaction in lamports by lowering the unit price, zero
+	// means the fee is not capped
+	MaxPriorityFee uint64 `fig:"max_priority_fee"`
+	// Escalation is applied to the unit limit and price once per every earlier transaction of the transfer
+	// that was dropped or failed
+	Escalation float64 `fig:"escalation"`
+}
+
+// Limit applies the multiplier, the bounds and the escalation of the resubmissions to the simulated units
+func (c SolanaComputeBudget) Limit(simulated uint64, resubmissions int) uint32 {
+	limit := c.Escalate(float64(simulated)*c.Multiplier, resubmissions)
+	if limit < float64(c.MinLimit) {
+		limit = float64(c.MinLimit)
+	}
+	if limit > float64(c.MaxLimit) {
+		limit = float64(c.MaxLimit)
+	}
+
+	return uint32(limit)
+}
+
+// Escalate raises the value once per resubmission
+func (c SolanaComputeBudget) Escalate(value float64, resubmissions int) float64 {
+	for i := 0; i < resubmissions; i++ {
+		value *= c.Escalation
+	}
+
+	return value
 }
 
 type solaner struct {
@@ -41,21 +114,58 @@ func NewSolaner(getter kv.Getter) Solaner {
 
 func (s *solaner) Solana() *Solana {
 	return s.once.Do(func() interface{} {
-		config := Solana{}
+		config := Solana{
+			ComputeBudget: SolanaComputeBudget{
+				Multiplier:    1.2,
+				MaxLimit:      maxSolanaComputeUnits,
+				FeePercentile: 75,
+				Escalation:    1.5,
+			},
+		}
 
 		if err := figure.Out(&config).With(solanaHooks, figure.BaseHooks).From(kv.MustGetStringMap(s.getter, "solana")).Please(); err != nil {
 			panic(errors.Wrap(err, "failed to figure out config for solana"))
 		}
-		bridgeAdmin, err := solana.CreateProgramAddress([][]byte{config.BridgeAdminSeed[:]}, config.BridgeProgramID)
+		budget := config.ComputeBudget
+		if budget.MaxLimit == 0 || budget.MaxLimit > maxSolanaComputeUnits || budget.MinLimit > budget.MaxLimit {
+			panic(errors.New("compute budget limits must satisfy min_limit <= max_limit <= 1400000"))
+		}
+		if budget.FeePercentile < 0 || budget.FeePercentile > 100 {
+			panic(errors.New("compute budget fee percentile must be within [0, 100]"))
+		}
+		if budget.MaxUnitPrice != 0 && budget.MinUnitPrice > budget.MaxUnitPrice {
+			panic(errors.New("compute budget min_unit_price must not exceed max_unit_price"))
+		}
+		if budget.Multiplier < 1 || budget.Escalation < 1 {
+			panic(errors.New("compute budget multiplier and escalation must be at least 1"))
+		}
+
+		if config.LookupTable.Managed && config.LookupTable.Authority.IsZero() {
+			panic(errors.New("authority is required for the managed lookup table"))
+		}
+
+		var err error
+		if !config.BridgeProgramID.IsZero() {
+			config.BridgeAdmin, err = config.DeriveBridgeAdmin(config.BridgeProgramID)
+			if err != nil {
+				panic(errors.Wrap(err, "failed to create program address"))
+			}
+		}
+
+		config.RPC, err = failover.NewPool("solana_rpc", config.RPCURLs, dialSolanaRPC, checkSolanaRPC)
 		if err != nil {
-			panic(errors.Wrap(err, "failed to create program address"))
+			panic(errors.Wrap(err, "failed to create solana rpc pool"))
 		}
-		config.BridgeAdmin = bridgeAdmin
 
 		return &config
 	}).(*Solana)
 }
 
+// DeriveBridgeAdmin returns the bridge admin account of the program created with the configured seed
+func (s *Solana) DeriveBridgeAdmin(programID solana.PublicKey) (solana.PublicKey, error) {
+	return solana.CreateProgramAddress([][]byte{s.BridgeAdminSeed[:]}, programID)
+}
+
 var solanaHooks = figure.Hooks{
 	"solana.PublicKey": func(value interface{}) (reflect.Value, error) {
 		rawPubKey, err := cast.ToStringE(value)
@@ -81,25 +191,18 @@ var solanaHooks = figure.Hooks{
 		}
 		return reflect.ValueOf(utils.ToByte32(seed)), nil
 	},
-	"*rpc.Client": func(value interface{}) (reflect.Value, error) {
-		rawRPC, err := cast.ToStringE(value)
-		if err != nil {
-			return reflect.Value{}, errors.Wrap(err, "expected a valid solana RPC URL")
-		}
+}
 
-		rpcClient := rpc.New(rawRPC)
-		return reflect.ValueOf(rpcClient), nil
-	},
-	"*ws.Client": func(value interface{}) (reflect.Value, error) {
-		rawWS, err := cast.ToStringE(value)
-		if err != nil {
-			return reflect.Value{}, errors.Wrap(err, "expected a valid solana WS URL")
-		}
+func dialSolanaRPC(url string) (*rpc.Client, error) {
+	return rpc.NewWithCustomRPCClient(jsonrpc.NewClientWithOpts(url, &jsonrpc.RPCClientOpts{
+		HTTPClient: tracedHTTPClient(),
+	})), nil
+}
 
-		wsClient, err := ws.Connect(context.Background(), rawWS)
-		if err != nil {
-			panic(errors.Wrap(err, "failed to open the socket"))
-		}
-		return reflect.ValueOf(wsClient), nil
-	},
+func checkSolanaRPC(ctx context.Context, client *rpc.Client) error {
+	if _, err := client.GetHealth(ctx); err != nil {
+		return errors.Wrap(err, "node is not healthy")
+	}
+
+	return nil
 }
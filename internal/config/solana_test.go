@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestSolanaComputeBudgetLimit(t *testing.T) {
+	budget := SolanaComputeBudget{
+		Multiplier: 1.2,
+		MinLimit:   50_000,
+		MaxLimit:   maxSolanaComputeUnits,
+		Escalation: 1.5,
+	}
+
+	cases := []struct {
+		name          string
+		simulated     uint64
+		resubmissions int
+		want          uint32
+	}{
+		{name: "margin", simulated: 100_000, want: 120_000},
+		{name: "min limit", simulated: 10_000, want: 50_000},
+		{name: "escalated", simulated: 100_000, resubmissions: 2, want: 270_000},
+		{name: "max limit", simulated: 1_300_000, want: maxSolanaComputeUnits},
+		{name: "escalated above max limit", simulated: 1_000_000, resubmissions: 1, want: maxSolanaComputeUnits},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := budget.Limit(tc.simulated, tc.resubmissions); got != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TracingExporterNone = "none"
+	TracingExporterOTLP = "otlp"
+	TracingExporterFile = "file"
+)
+
+type Tracinger interface {
+	// Tracing sets up the global tracer provider and returns it
+	Tracing() Tracing
+}
+
+type Tracing interface {
+	trace.TracerProvider
+	// Shutdown flushes the pending spans and stops the exporter
+	Shutdown(ctx context.Context) error
+}
+
+type TracingConfig struct {
+	Exporter    string  `fig:"exporter"`
+	Endpoint    string  `fig:"endpoint"`
+	Insecure    bool    `fig:"insecure"`
+	File        string  `fig:"file"`
+	ServiceName string  `fig:"service_name"`
+	SampleRatio float64 `fig:"sample_ratio"`
+}
+
+type tracinger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewTracinger(getter kv.Getter) Tracinger {
+	return &tracinger{
+		getter: getter,
+	}
+}
+
+func (t *tracinger) Tracing() Tracing {
+	return t.once.Do(func() interface{} {
+		cfg := TracingConfig{
+			Exporter:    TracingExporterNone,
+			ServiceName: "relayer-svc",
+			SampleRatio: 1,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(t.getter, "tracing")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out tracing config"))
+		}
+
+		exporter, closer, err := newSpanExporter(cfg)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to create span exporter"))
+		}
+
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		))
+
+		if exporter == nil {
+			return noopTracing{TracerProvider: trace.NewNoopTracerProvider()}
+		}
+
+		provider := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+			sdktrace.WithResource(resource.NewWithAttributes(
+				semconv.SchemaURL,
+				semconv.ServiceName(cfg.ServiceName),
+			)),
+		)
+		otel.SetTracerProvider(provider)
+
+		if closer != nil {
+			return closingTracing{TracerProvider: provider, closer: closer}
+		}
+
+		return provider
+	}).(Tracing)
+}
+
+// newSpanExporter returns the exporter and the output it writes to, the output has to be closed once the
+// exporter is shut down
+func newSpanExporter(cfg TracingConfig) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case TracingExporterNone, "":
+		return nil, nil, nil
+	case TracingExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(context.Background(), opts...)
+		return exporter, nil, err
+	case TracingExporterFile:
+		if cfg.File == "" {
+			return nil, nil, errors.New("file is required for the file exporter")
+		}
+
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to open the traces file")
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+
+		return exporter, file, nil
+	default:
+		return nil, nil, errors.Errorf("unknown tracing exporter: %s", cfg.Exporter)
+	}
+}
+
+type noopTracing struct {
+	trace.TracerProvider
+}
+
+func (noopTracing) Shutdown(context.Context) error {
+	return nil
+}
+
+// closingTracing closes the exporter output after the pending spans are flushed into it
+type closingTracing struct {
+	*sdktrace.TracerProvider
+	closer io.Closer
+}
+
+func (t closingTracing) Shutdown(ctx context.Context) error {
+	err := t.TracerProvider.Shutdown(ctx)
+	if closeErr := t.closer.Close(); err == nil && closeErr != nil {
+		err = errors.Wrap(closeErr, "failed to close the traces output")
+	}
+
+	return err
+}
+
+// tracedHTTPClient returns the HTTP client that reports every outgoing RPC request as a client span
+func tracedHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   5 * time.Minute,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/distributed_lab/kit/kv"
+
+	"github.com/rarimo/relayer-svc/internal/tracing"
+)
+
+func TestFileTracing(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	provider := NewTracinger(kv.GetterFunc(func(key string) (map[string]interface{}, error) {
+		return map[string]interface{}{"exporter": TracingExporterFile, "file": file}, nil
+	})).Tracing()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	defaultTransport := http.DefaultTransport
+	client, err := dialNear(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if http.DefaultTransport != defaultTransport {
+		t.Error("expected the default transport to be left intact")
+	}
+
+	// the response is not a valid block, only the request matters
+	ctx, span := tracing.Start(context.Background(), "relayer.test")
+	_ = checkNearRPC(ctx, client)
+	tracing.End(span, nil)
+
+	if err = provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shut down the tracing: %v", err)
+	}
+
+	closing, ok := provider.(closingTracing)
+	if !ok {
+		t.Fatalf("expected the file output to be closed on shutdown, got %T", provider)
+	}
+	if _, err = closing.closer.(*os.File).Write([]byte("{}")); err == nil {
+		t.Error("expected the traces file to be closed")
+	}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traces := string(raw)
+	for _, want := range []string{`"Name":"relayer.test"`, `"Name":"HTTP POST"`} {
+		if !strings.Contains(traces, want) {
+			t.Errorf("expected the traces to contain %s, got %s", want, traces)
+		}
+	}
+}
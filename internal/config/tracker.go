@@ -0,0 +1,61 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type Trackerer interface {
+	Tracker() *TrackerConfig
+}
+
+type TrackerConfig struct {
+	// Interval is the period the submitted transactions are checked with
+	Interval time.Duration `fig:"interval"`
+	// MaxPending is the period after which the transfer without the final transaction is submitted again
+	MaxPending time.Duration `fig:"max_pending"`
+	// Workers is the number of the submissions checked concurrently
+	Workers int `fig:"workers"`
+	// MaxResubmissions is the number of the transactions of the transfer that may run out of gas before
+	// it is dead-lettered
+	MaxResubmissions int `fig:"max_resubmissions"`
+}
+
+type trackerer struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewTrackerer(getter kv.Getter) Trackerer {
+	return &trackerer{
+		getter: getter,
+	}
+}
+
+func (t *trackerer) Tracker() *TrackerConfig {
+	return t.once.Do(func() interface{} {
+		cfg := TrackerConfig{
+			Interval:         5 * time.Second,
+			MaxPending:       time.Hour,
+			Workers:          10,
+			MaxResubmissions: 5,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(t.getter, "tracker")).
+			Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out tracker config"))
+		}
+		if cfg.Workers < 1 {
+			panic(errors.New("tracker needs at least one worker"))
+		}
+
+		return &cfg
+	}).(*TrackerConfig)
+}
@@ -13,4 +13,6 @@ type TransferDetails struct {
 	Signature      string
 	Origin         string
 	MerklePath     [][32]byte
+	// Resubmissions is the number of the earlier transactions of the transfer that were dropped or failed
+	Resubmissions int
 }
@@ -0,0 +1,196 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	pausedChainsKey  = "paused_chains"
+	breakerStatesKey = "breaker_states"
+	// breakerFieldSeparator separates the chain from the endpoint index in the breaker states, the index is
+	// looked up as the last part since the chain names are free-form
+	breakerFieldSeparator = "/"
+	unavailableChainsKey  = "unavailable_chains"
+	parkedChainsKey       = "parked_chains"
+	parkedTasksKeyPrefix  = "parked_tasks:"
+
+	// PauseReasonManual is set when the chain is paused by the admin
+	PauseReasonManual = "manual"
+	// PauseReasonLowFunds is set when the signer balance dropped below the critical threshold
+	PauseReasonLowFunds = "low_funds"
+)
+
+// resumeChainScript removes the paused flag only if the chain is still paused with the reason
+var resumeChainScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call("HDEL", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+func (r *rediser) PauseChain(ctx context.Context, chain, reason string) error {
+	// the manual pause takes over the automatic one, while the automatic pause never overwrites the manual
+	// one made in the meantime, otherwise the chain would be resumed automatically later
+	var err error
+	if reason == PauseReasonManual {
+		err = r.client.HSet(ctx, pausedChainsKey, chain, reason).Err()
+	} else {
+		err = r.client.HSetNX(ctx, pausedChainsKey, chain, reason).Err()
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to set the chain paused flag")
+	}
+
+	return nil
+}
+
+func (r *rediser) ResumeChain(ctx context.Context, chain string) error {
+	if err := r.client.HDel(ctx, pausedChainsKey, chain).Err(); err != nil {
+		return errors.Wrap(err, "failed to remove the chain paused flag")
+	}
+
+	return nil
+}
+
+func (r *rediser) ResumeChainPausedFor(ctx context.Context, chain, reason string) (bool, error) {
+	removed, err := resumeChainScript.Run(ctx, r.client, []string{pausedChainsKey}, chain, reason).Int64()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to remove the chain paused flag")
+	}
+
+	return removed > 0, nil
+}
+
+func (r *rediser) ChainPauseReason(ctx context.Context, chain string) (string, bool, error) {
+	reason, err := r.client.HGet(ctx, pausedChainsKey, chain).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to get the chain paused flag")
+	}
+
+	return reason, true, nil
+}
+
+func (r *rediser) PausedChains(ctx context.Context) (map[string]string, error) {
+	paused, err := r.client.HGetAll(ctx, pausedChainsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get paused chains")
+	}
+
+	return paused, nil
+}
+
+func (r *rediser) SetBreakerState(ctx context.Context, chain string, endpoint int, state string) error {
+	if err := r.client.HSet(ctx, breakerStatesKey, breakerField(chain, endpoint), state).Err(); err != nil {
+		return errors.Wrap(err, "failed to set the circuit breaker state")
+	}
+
+	return nil
+}
+
+func (r *rediser) BreakerStates(ctx context.Context) (map[string]map[string]string, error) {
+	fields, err := r.client.HGetAll(ctx, breakerStatesKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get circuit breaker states")
+	}
+
+	states := make(map[string]map[string]string)
+	for field, state := range fields {
+		// the states stored by chain before the breakers were kept per endpoint are skipped
+		separator := strings.LastIndex(field, breakerFieldSeparator)
+		if separator < 0 {
+			continue
+		}
+
+		chain, endpoint := field[:separator], field[separator+1:]
+		if states[chain] == nil {
+			states[chain] = make(map[string]string)
+		}
+		states[chain][endpoint] = state
+	}
+
+	return states, nil
+}
+
+func breakerField(chain string, endpoint int) string {
+	return chain + breakerFieldSeparator + strconv.Itoa(endpoint)
+}
+
+func (r *rediser) SetChainAvailable(ctx context.Context, chain string, available bool) error {
+	if available {
+		if err := r.client.HDel(ctx, unavailableChainsKey, chain).Err(); err != nil {
+			return errors.Wrap(err, "failed to remove the chain unavailable flag")
+		}
+
+		return nil
+	}
+
+	// keeping the time the chain became unavailable at first
+	if err := r.client.HSetNX(ctx, unavailableChainsKey, chain, time.Now().UTC().Format(time.RFC3339)).Err(); err != nil {
+		return errors.Wrap(err, "failed to set the chain unavailable flag")
+	}
+
+	return nil
+}
+
+func (r *rediser) UnavailableChains(ctx context.Context) (map[string]string, error) {
+	chains, err := r.client.HGetAll(ctx, unavailableChainsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get unavailable chains")
+	}
+
+	return chains, nil
+}
+
+func (r *rediser) ParkTask(ctx context.Context, chain string, task []byte) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, parkedTasksKeyPrefix+chain, task)
+		pipe.SAdd(ctx, parkedChainsKey, chain)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to park the task")
+	}
+
+	return nil
+}
+
+func (r *rediser) ParkedChains(ctx context.Context) ([]string, error) {
+	chains, err := r.client.SMembers(ctx, parkedChainsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get chains with parked tasks")
+	}
+
+	return chains, nil
+}
+
+func (r *rediser) UnparkTasks(ctx context.Context, chain string) (int64, error) {
+	var count int64
+	for {
+		task, err := r.client.LPop(ctx, parkedTasksKeyPrefix+chain).Bytes()
+		if err == redis.Nil {
+			return count, nil
+		}
+		if err != nil {
+			return count, errors.Wrap(err, "failed to pop the parked task")
+		}
+
+		if err = r.OpenRelayQueue(chain).PublishBytes(task); err != nil {
+			if pushErr := r.client.LPush(ctx, parkedTasksKeyPrefix+chain, task).Err(); pushErr != nil {
+				return count, errors.Wrap(pushErr, "failed to return the task to the parked list")
+			}
+
+			return count, errors.Wrap(err, "failed to publish the parked task")
+		}
+
+		count++
+	}
+}
@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestChainPause(t *testing.T) {
+	const chain = "ethereum"
+
+	cases := []struct {
+		name string
+		// before is the reason the chain is paused with already, empty if it is not paused
+		before      string
+		pause       string
+		resumeFor   string
+		wantResumed bool
+		wantReason  string
+	}{
+		{
+			name:       "automatic pause",
+			pause:      PauseReasonLowFunds,
+			wantReason: PauseReasonLowFunds,
+		},
+		{
+			name:       "automatic pause keeps the manual one",
+			before:     PauseReasonManual,
+			pause:      PauseReasonLowFunds,
+			wantReason: PauseReasonManual,
+		},
+		{
+			name:       "manual pause replaces the automatic one",
+			before:     PauseReasonLowFunds,
+			pause:      PauseReasonManual,
+			wantReason: PauseReasonManual,
+		},
+		{
+			name:        "automatic resume of the automatic pause",
+			before:      PauseReasonLowFunds,
+			resumeFor:   PauseReasonLowFunds,
+			wantResumed: true,
+		},
+		{
+			name:       "automatic resume keeps the manual pause",
+			before:     PauseReasonManual,
+			resumeFor:  PauseReasonLowFunds,
+			wantReason: PauseReasonManual,
+		},
+		{
+			name:      "automatic resume of the running chain",
+			resumeFor: PauseReasonLowFunds,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			r := &rediser{client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+			ctx := context.Background()
+
+			if tc.before != "" {
+				server.HSet(pausedChainsKey, chain, tc.before)
+			}
+
+			if tc.pause != "" {
+				if err := r.PauseChain(ctx, chain, tc.pause); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if tc.resumeFor != "" {
+				resumed, err := r.ResumeChainPausedFor(ctx, chain, tc.resumeFor)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if resumed != tc.wantResumed {
+					t.Fatalf("got resumed %v, want %v", resumed, tc.wantResumed)
+				}
+			}
+
+			reason, paused, err := r.ChainPauseReason(ctx, chain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if paused != (tc.wantReason != "") || reason != tc.wantReason {
+				t.Fatalf("got reason %q, want %q", reason, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestBreakerStates(t *testing.T) {
+	server := miniredis.RunT(t)
+	r := &rediser{client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+	ctx := context.Background()
+
+	// the state stored by chain before the breakers were kept per endpoint
+	server.HSet(breakerStatesKey, "ethereum", "open")
+
+	reported := []struct {
+		chain    string
+		endpoint int
+		state    string
+	}{
+		{chain: "ethereum", endpoint: 0, state: "open"},
+		{chain: "ethereum", endpoint: 1, state: "closed"},
+		{chain: "bsc/testnet", endpoint: 0, state: "half-open"},
+	}
+	for _, report := range reported {
+		if err := r.SetBreakerState(ctx, report.chain, report.endpoint, report.state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	states, err := r.BreakerStates(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]map[string]string{
+		"ethereum":    {"0": "open", "1": "closed"},
+		"bsc/testnet": {"0": "half-open"},
+	}
+	if !reflect.DeepEqual(states, want) {
+		t.Fatalf("got %v, want %v", states, want)
+	}
+}
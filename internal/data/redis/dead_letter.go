@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const deadLetterTasksKey = "dead_letter_tasks"
+
+// DeadLetter is the task that was removed from the relay queue with the reason it failed with
+type DeadLetter struct {
+	Task     json.RawMessage `json:"task"`
+	Reason   string          `json:"reason"`
+	FailedAt time.Time       `json:"failed_at"`
+}
+
+func (r *rediser) DeadLetterTask(ctx context.Context, task []byte, reason string) error {
+	letter, err := json.Marshal(DeadLetter{
+		Task:     task,
+		Reason:   reason,
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the dead letter")
+	}
+
+	if err = r.client.RPush(ctx, deadLetterTasksKey, letter).Err(); err != nil {
+		return errors.Wrap(err, "failed to push the dead letter")
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const lookupTablesKey = "solana_lookup_tables"
+
+func (r *rediser) LookupTable(ctx context.Context, chain string) (string, error) {
+	address, err := r.client.HGet(ctx, lookupTablesKey, chain).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the lookup table")
+	}
+
+	return address, nil
+}
+
+func (r *rediser) SetLookupTable(ctx context.Context, chain, address string) (string, error) {
+	if err := r.client.HSetNX(ctx, lookupTablesKey, chain, address).Err(); err != nil {
+		return "", errors.Wrap(err, "failed to set the lookup table")
+	}
+
+	// the table of the replica that has stored it first is used by all of them
+	return r.LookupTable(ctx, chain)
+}
@@ -1,9 +1,12 @@
 package redis
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/adjust/rmq/v5"
 	"github.com/redis/go-redis/v9"
-	"gitlab.com/distributed_lab/kit/comfig"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
@@ -11,15 +14,98 @@ import (
 type Rediser interface {
 	Client() *redis.Client
 	CleanQueues() (int64, error)
-	OpenRelayQueue() rmq.Queue
+	// OpenRelayQueue returns the relay queue of the destination chain, so the relayer instances may serve
+	// different chains. The empty chain is the shared queue of the tasks scheduled before the split.
+	OpenRelayQueue(chain string) rmq.Queue
+
+	// PauseChain stops relaying to the chain, the reason tells manual pauses from the automatic ones. Only the
+	// manual pause replaces the existing one.
+	PauseChain(ctx context.Context, chain, reason string) error
+	// ResumeChain allows relaying to the chain again
+	ResumeChain(ctx context.Context, chain string) error
+	// ResumeChainPausedFor resumes the chain only if it is paused with the reason, false is returned otherwise
+	ResumeChainPausedFor(ctx context.Context, chain, reason string) (bool, error)
+	// ChainPauseReason returns the reason the chain was paused with and whether it is paused at all
+	ChainPauseReason(ctx context.Context, chain string) (string, bool, error)
+	// PausedChains returns the paused chains mapped to the pause reasons
+	PausedChains(ctx context.Context) (map[string]string, error)
+
+	// SetBreakerState stores the circuit breaker state of the chain endpoint reported by the relayer
+	SetBreakerState(ctx context.Context, chain string, endpoint int, state string) error
+	// BreakerStates returns the last reported circuit breaker states mapped by chain and endpoint index
+	BreakerStates(ctx context.Context) (map[string]map[string]string, error)
+
+	// ParkTask keeps the task aside until relaying to the chain is possible again
+	ParkTask(ctx context.Context, chain string, task []byte) error
+	// ParkedChains returns the chains that may have parked tasks
+	ParkedChains(ctx context.Context) ([]string, error)
+	// UnparkTasks publishes the parked tasks of the chain back to the relay queue
+	UnparkTasks(ctx context.Context, chain string) (int64, error)
+
+	// SetChainAvailable stores whether any endpoint of the chain is healthy
+	SetChainAvailable(ctx context.Context, chain string, available bool) error
+	// UnavailableChains returns the chains without healthy endpoints mapped to the time they became unavailable
+	UnavailableChains(ctx context.Context) (map[string]string, error)
+
+	// SetVaultStatus stores the failure of the last Vault refresh of the relayer instance for the ttl, nil
+	// clears it
+	SetVaultStatus(ctx context.Context, instance string, failure error, ttl time.Duration) error
+	// VaultFailures returns the failing Vault refreshes mapped by the relayer instance
+	VaultFailures(ctx context.Context) (map[string]VaultFailure, error)
+
+	// LookupTable returns the address of the Solana lookup table managed by the relayer, empty if it is
+	// not created yet
+	LookupTable(ctx context.Context, chain string) (string, error)
+	// SetLookupTable stores the created lookup table unless the other replica has stored its one, the stored
+	// address is returned
+	SetLookupTable(ctx context.Context, chain, address string) (string, error)
+
+	// DrainSigner stops selecting the signer for the new withdrawals to the chain, the submitted ones are
+	// still confirmed with it
+	DrainSigner(ctx context.Context, chain, signer, reason string) error
+	// UndrainSigner allows selecting the signer again
+	UndrainSigner(ctx context.Context, chain, signer string) error
+	// UndrainSignerDrainedFor undrains the signer only if it is drained with the reason, false is returned
+	// otherwise
+	UndrainSignerDrainedFor(ctx context.Context, chain, signer, reason string) (bool, error)
+	// DrainedSigners returns the drained signers of the chain mapped to the drain reasons
+	DrainedSigners(ctx context.Context, chain string) (map[string]string, error)
+	// AddSignerLoad changes the number of the withdrawals submitted by the signer and not final yet
+	AddSignerLoad(ctx context.Context, chain, signer string, delta int64) error
+	// SignerLoads returns the numbers of the unconfirmed withdrawals mapped by the chain signers
+	SignerLoads(ctx context.Context, chain string) (map[string]int64, error)
+
+	// DeadLetterTask keeps the task that can never succeed aside for the manual investigation
+	DeadLetterTask(ctx context.Context, task []byte, reason string) error
+
+	// AddPendingTask stores the task with the withdrawal submitted, so its confirmation is tracked
+	AddPendingTask(ctx context.Context, origin string, task []byte) error
+	// PendingTasks returns the tasks awaiting the confirmation mapped by the transfer origin
+	PendingTasks(ctx context.Context) (map[string]string, error)
+	// RemovePendingTask returns false if the task was removed already, e.g. by the other replica
+	RemovePendingTask(ctx context.Context, origin string) (bool, error)
+
+	// RecordTaskEvent appends the event to the history of the transfer identified by its origin
+	RecordTaskEvent(ctx context.Context, origin string, event TaskEvent) error
+	// TaskHistory returns the events of the transfer in the order they were recorded
+	TaskHistory(ctx context.Context, origin string) ([]TaskEvent, error)
+	// StoreTaskResult saves the result of the confirmed withdrawal of the transfer
+	StoreTaskResult(ctx context.Context, origin string, result []byte) error
+	// TaskResult returns the saved result of the withdrawal, nil if the transfer is not relayed yet
+	TaskResult(ctx context.Context, origin string) ([]byte, error)
 }
 
+// sharedRelayQueue holds the tasks scheduled before the relay queues were split by chain, the queues of
+// the chains are prefixed with its name
+const sharedRelayQueue = "relay"
+
 type rediser struct {
 	client     *redis.Client
 	connection rmq.Connection
 	cleaner    *rmq.Cleaner
 
-	relayQueueOnce comfig.Once
+	relayQueuesMu sync.Mutex
+	relayQueues   map[string]rmq.Queue
 }
 
 func (r *rediser) Client() *redis.Client {
@@ -30,15 +116,26 @@ func (r *rediser) CleanQueues() (int64, error) {
 	return r.cleaner.Clean()
 }
 
-func (r *rediser) OpenRelayQueue() rmq.Queue {
-	return r.relayQueueOnce.Do(func() interface{} {
-		taskQueue, err := r.connection.OpenQueue("relay")
-		if err != nil {
-			panic(errors.Wrap(err, "failed to open a task queue"))
-		}
+func (r *rediser) OpenRelayQueue(chain string) rmq.Queue {
+	r.relayQueuesMu.Lock()
+	defer r.relayQueuesMu.Unlock()
+
+	if queue, ok := r.relayQueues[chain]; ok {
+		return queue
+	}
+
+	name := sharedRelayQueue
+	if chain != "" {
+		name = sharedRelayQueue + ":" + chain
+	}
+
+	taskQueue, err := r.connection.OpenQueue(name)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to open a task queue", logan.F{"queue": name}))
+	}
 
-		return taskQueue
-	}).(rmq.Queue)
+	r.relayQueues[chain] = taskQueue
+	return taskQueue
 }
 
 func NewRediser(cfg config, log *logan.Entry) Rediser {
@@ -59,8 +156,9 @@ func NewRediser(cfg config, log *logan.Entry) Rediser {
 	}()
 
 	return &rediser{
-		client:     client,
-		connection: connection,
-		cleaner:    rmq.NewCleaner(connection),
+		client:      client,
+		connection:  connection,
+		cleaner:     rmq.NewCleaner(connection),
+		relayQueues: make(map[string]rmq.Queue),
 	}
 }
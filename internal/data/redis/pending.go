@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const pendingTasksKey = "pending_tasks"
+
+func (r *rediser) AddPendingTask(ctx context.Context, origin string, task []byte) error {
+	if err := r.client.HSet(ctx, pendingTasksKey, origin, task).Err(); err != nil {
+		return errors.Wrap(err, "failed to add the pending task")
+	}
+
+	return nil
+}
+
+func (r *rediser) PendingTasks(ctx context.Context) (map[string]string, error) {
+	tasks, err := r.client.HGetAll(ctx, pendingTasksKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pending tasks")
+	}
+
+	return tasks, nil
+}
+
+func (r *rediser) RemovePendingTask(ctx context.Context, origin string) (bool, error) {
+	removed, err := r.client.HDel(ctx, pendingTasksKey, origin).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to remove the pending task")
+	}
+
+	return removed > 0, nil
+}
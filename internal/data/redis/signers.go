@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	drainedSignersKeyPrefix = "drained_signers:"
+	signerLoadsKeyPrefix    = "signer_loads:"
+
+	// DrainReasonManual is set when the signer is drained by the admin, e.g. for the key rotation
+	DrainReasonManual = "manual"
+	// DrainReasonLowFunds is set when the signer balance dropped below the critical threshold
+	DrainReasonLowFunds = "low_funds"
+)
+
+// NormalizeSigner returns the signer address the drains and the loads are stored under. The EVM addresses are
+// lowered, so they match regardless of the checksum, the rest of the addresses are case-sensitive.
+func NormalizeSigner(signer string) string {
+	if common.IsHexAddress(signer) {
+		return strings.ToLower(signer)
+	}
+
+	return signer
+}
+
+// undrainSignerScript removes the drained flags of the signer only if they are set with the reason
+var undrainSignerScript = redis.NewScript(`
+local removed = 0
+for i = 2, #ARGV do
+	if redis.call("HGET", KEYS[1], ARGV[i]) == ARGV[1] then
+		removed = removed + redis.call("HDEL", KEYS[1], ARGV[i])
+	end
+end
+return removed
+`)
+
+func (r *rediser) DrainSigner(ctx context.Context, chain, signer, reason string) error {
+	// the manual drain takes over the automatic one, while the automatic drain never overwrites the manual
+	// one made in the meantime, otherwise the signer would be undrained automatically later
+	var err error
+	if reason == DrainReasonManual {
+		err = r.client.HSet(ctx, drainedSignersKeyPrefix+chain, NormalizeSigner(signer), reason).Err()
+	} else {
+		err = r.client.HSetNX(ctx, drainedSignersKeyPrefix+chain, NormalizeSigner(signer), reason).Err()
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to set the signer drained flag")
+	}
+
+	return nil
+}
+
+func (r *rediser) UndrainSigner(ctx context.Context, chain, signer string) error {
+	// the signer drained before the addresses were normalized is stored as it was passed
+	if err := r.client.HDel(ctx, drainedSignersKeyPrefix+chain, NormalizeSigner(signer), signer).Err(); err != nil {
+		return errors.Wrap(err, "failed to remove the signer drained flag")
+	}
+
+	return nil
+}
+
+func (r *rediser) UndrainSignerDrainedFor(ctx context.Context, chain, signer, reason string) (bool, error) {
+	removed, err := undrainSignerScript.Run(ctx, r.client, []string{drainedSignersKeyPrefix + chain},
+		reason, NormalizeSigner(signer), signer).Int64()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to remove the signer drained flag")
+	}
+
+	return removed > 0, nil
+}
+
+func (r *rediser) DrainedSigners(ctx context.Context, chain string) (map[string]string, error) {
+	raw, err := r.client.HGetAll(ctx, drainedSignersKeyPrefix+chain).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get drained signers")
+	}
+
+	drained := make(map[string]string, len(raw))
+	for signer, reason := range raw {
+		drained[NormalizeSigner(signer)] = reason
+	}
+
+	return drained, nil
+}
+
+func (r *rediser) AddSignerLoad(ctx context.Context, chain, signer string, delta int64) error {
+	if err := r.client.HIncrBy(ctx, signerLoadsKeyPrefix+chain, NormalizeSigner(signer), delta).Err(); err != nil {
+		return errors.Wrap(err, "failed to update the signer load")
+	}
+
+	return nil
+}
+
+func (r *rediser) SignerLoads(ctx context.Context, chain string) (map[string]int64, error) {
+	raw, err := r.client.HGetAll(ctx, signerLoadsKeyPrefix+chain).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get signer loads")
+	}
+
+	loads := make(map[string]int64, len(raw))
+	for signer, rawLoad := range raw {
+		load, err := strconv.ParseInt(rawLoad, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed signer load")
+		}
+		loads[NormalizeSigner(signer)] += load
+	}
+
+	return loads, nil
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSignerDrain(t *testing.T) {
+	const (
+		chain      = "ethereum"
+		checksum   = "0x52908400098527886E0F7030069857D2E4169EE7"
+		normalized = "0x52908400098527886e0f7030069857d2e4169ee7"
+	)
+
+	cases := []struct {
+		name string
+		// before is the reason the signer is drained with already under the stored address, empty if it is not
+		before        string
+		storedAs      string
+		drain         string
+		undrainFor    string
+		wantUndrained bool
+		wantReason    string
+	}{
+		{
+			name:       "automatic drain",
+			drain:      DrainReasonLowFunds,
+			wantReason: DrainReasonLowFunds,
+		},
+		{
+			name:       "automatic drain keeps the manual one",
+			before:     DrainReasonManual,
+			storedAs:   normalized,
+			drain:      DrainReasonLowFunds,
+			wantReason: DrainReasonManual,
+		},
+		{
+			name:       "manual drain replaces the automatic one",
+			before:     DrainReasonLowFunds,
+			storedAs:   normalized,
+			drain:      DrainReasonManual,
+			wantReason: DrainReasonManual,
+		},
+		{
+			name:          "automatic undrain of the automatic drain",
+			before:        DrainReasonLowFunds,
+			storedAs:      normalized,
+			undrainFor:    DrainReasonLowFunds,
+			wantUndrained: true,
+		},
+		{
+			name:          "automatic undrain of the drain stored before the normalization",
+			before:        DrainReasonLowFunds,
+			storedAs:      checksum,
+			undrainFor:    DrainReasonLowFunds,
+			wantUndrained: true,
+		},
+		{
+			name:       "automatic undrain keeps the manual drain",
+			before:     DrainReasonManual,
+			storedAs:   normalized,
+			undrainFor: DrainReasonLowFunds,
+			wantReason: DrainReasonManual,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			r := &rediser{client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+			ctx := context.Background()
+
+			if tc.before != "" {
+				server.HSet(drainedSignersKeyPrefix+chain, tc.storedAs, tc.before)
+			}
+
+			if tc.drain != "" {
+				if err := r.DrainSigner(ctx, chain, checksum, tc.drain); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if tc.undrainFor != "" {
+				undrained, err := r.UndrainSignerDrainedFor(ctx, chain, checksum, tc.undrainFor)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if undrained != tc.wantUndrained {
+					t.Fatalf("got undrained %v, want %v", undrained, tc.wantUndrained)
+				}
+			}
+
+			drained, err := r.DrainedSigners(ctx, chain)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reason := drained[NormalizeSigner(checksum)]; reason != tc.wantReason {
+				t.Fatalf("got reason %q, want %q", reason, tc.wantReason)
+			}
+		})
+	}
+}
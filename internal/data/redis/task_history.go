@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	taskHistoryKeyPrefix = "task_history:"
+	taskResultKeyPrefix  = "task_result:"
+	// taskHistoryTTL keeps the history long enough to investigate the transfer after it is relayed
+	taskHistoryTTL = 30 * 24 * time.Hour
+
+	// TaskEventSubmitted is recorded when the withdrawal transaction is sent
+	TaskEventSubmitted = "submitted"
+	// TaskEventReplaced is recorded when the stuck transaction is re-sent with the bumped fees
+	TaskEventReplaced = "replaced"
+	// TaskEventMined is recorded when the withdrawal transaction is first seen included in the block
+	TaskEventMined = "mined"
+	// TaskEventConfirmed is recorded when the withdrawal transaction is final
+	TaskEventConfirmed = "confirmed"
+)
+
+// TaskEvent is the step of relaying the transfer to the destination chain
+type TaskEvent struct {
+	Event  string    `json:"event"`
+	Chain  string    `json:"chain"`
+	TxHash string    `json:"tx_hash,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+func (r *rediser) RecordTaskEvent(ctx context.Context, origin string, event TaskEvent) error {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the task event")
+	}
+
+	key := taskHistoryKeyPrefix + origin
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, raw)
+		pipe.Expire(ctx, key, taskHistoryTTL)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to record the task event")
+	}
+
+	return nil
+}
+
+func (r *rediser) TaskHistory(ctx context.Context, origin string) ([]TaskEvent, error) {
+	raw, err := r.client.LRange(ctx, taskHistoryKeyPrefix+origin, 0, -1).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the task history")
+	}
+
+	events := make([]TaskEvent, len(raw))
+	for i, rawEvent := range raw {
+		if err = json.Unmarshal([]byte(rawEvent), &events[i]); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the task event")
+		}
+	}
+
+	return events, nil
+}
+
+func (r *rediser) StoreTaskResult(ctx context.Context, origin string, result []byte) error {
+	if err := r.client.Set(ctx, taskResultKeyPrefix+origin, result, taskHistoryTTL).Err(); err != nil {
+		return errors.Wrap(err, "failed to store the task result")
+	}
+
+	return nil
+}
+
+func (r *rediser) TaskResult(ctx context.Context, origin string) ([]byte, error) {
+	raw, err := r.client.Get(ctx, taskResultKeyPrefix+origin).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the task result")
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	// vaultStatusKeyPrefix keeps the status of every relayer instance apart, so the failing one is named
+	vaultStatusKeyPrefix = "vault_status:"
+
+	vaultStatusError        = "error"
+	vaultStatusFailingSince = "failing_since"
+)
+
+// VaultFailure is the failing Vault refresh of the relayer instance
+type VaultFailure struct {
+	Error        string
+	FailingSince string
+}
+
+func (r *rediser) SetVaultStatus(ctx context.Context, instance string, failure error, ttl time.Duration) error {
+	key := vaultStatusKeyPrefix + instance
+	if failure == nil {
+		if err := r.client.Del(ctx, key).Err(); err != nil {
+			return errors.Wrap(err, "failed to remove the vault failure", logan.F{"instance": instance})
+		}
+
+		return nil
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, vaultStatusError, failure.Error())
+		// keeping the time the refresh failed at first
+		pipe.HSetNX(ctx, key, vaultStatusFailingSince, time.Now().UTC().Format(time.RFC3339))
+		// the status of the instance that stopped is dropped once it is not refreshed
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to set the vault failure", logan.F{"instance": instance})
+	}
+
+	return nil
+}
+
+func (r *rediser) VaultFailures(ctx context.Context) (map[string]VaultFailure, error) {
+	failures := make(map[string]VaultFailure)
+
+	iter := r.client.Scan(ctx, 0, vaultStatusKeyPrefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		status, err := r.client.HGetAll(ctx, iter.Val()).Result()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the vault status", logan.F{"key": iter.Val()})
+		}
+		// the status expired after it was scanned
+		if len(status) == 0 {
+			continue
+		}
+
+		failures[strings.TrimPrefix(iter.Val(), vaultStatusKeyPrefix)] = VaultFailure{
+			Error:        status[vaultStatusError],
+			FailingSince: status[vaultStatusFailingSince],
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to scan the vault statuses")
+	}
+
+	return failures, nil
+}
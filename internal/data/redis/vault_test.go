@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestVaultStatus(t *testing.T) {
+	const ttl = time.Minute
+
+	refreshErr := errors.New("permission denied")
+
+	cases := []struct {
+		name string
+		// failing are the instances whose refresh fails, the rest succeed
+		failing []string
+		// recovered are the instances whose refresh succeeds after failing
+		recovered []string
+		// elapsed is the time passed since the statuses are stored
+		elapsed time.Duration
+		want    []string
+	}{
+		{
+			name: "healthy instances",
+		},
+		{
+			name:    "failing instances are kept apart",
+			failing: []string{"relayer-a", "relayer-b"},
+			want:    []string{"relayer-a", "relayer-b"},
+		},
+		{
+			name:      "recovered instance",
+			failing:   []string{"relayer-a", "relayer-b"},
+			recovered: []string{"relayer-b"},
+			want:      []string{"relayer-a"},
+		},
+		{
+			name:    "stopped instance",
+			failing: []string{"relayer-a"},
+			elapsed: 2 * ttl,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			r := &rediser{client: redis.NewClient(&redis.Options{Addr: server.Addr()})}
+			ctx := context.Background()
+
+			for _, instance := range tc.failing {
+				if err := r.SetVaultStatus(ctx, instance, refreshErr, ttl); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			for _, instance := range tc.recovered {
+				if err := r.SetVaultStatus(ctx, instance, nil, ttl); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			server.FastForward(tc.elapsed)
+
+			failures, err := r.VaultFailures(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(failures) != len(tc.want) {
+				t.Fatalf("got %d failing instances, want %d", len(failures), len(tc.want))
+			}
+			for _, instance := range tc.want {
+				failure, ok := failures[instance]
+				if !ok {
+					t.Fatalf("instance %s is not reported", instance)
+				}
+				if failure.Error != refreshErr.Error() || failure.FailingSince == "" {
+					t.Fatalf("unexpected failure of %s: %+v", instance, failure)
+				}
+			}
+		})
+	}
+}
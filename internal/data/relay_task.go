@@ -1,10 +1,12 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/tracing"
 	"github.com/rarimo/relayer-svc/internal/utils"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
@@ -14,17 +16,26 @@ type RelayTask struct {
 	Signature      string
 	Origin         string
 	MerklePath     []string
+	// ToChain is the destination chain, it is empty for the tasks scheduled before it was introduced
+	ToChain string `json:",omitempty"`
 
 	RetriesLeft int
+	// Resubmissions counts the transactions of the task that were dropped or failed, the bridgers pay more
+	// for the next ones
+	Resubmissions int `json:",omitempty"`
+	// TraceContext carries the trace of the confirmation processing to the relayer
+	TraceContext tracing.Carrier `json:",omitempty"`
 }
 
-func NewRelayTask(transfer core.TransferDetails, maxRetries int) RelayTask {
+func NewRelayTask(ctx context.Context, transfer core.TransferDetails, maxRetries int) RelayTask {
 	task := RelayTask{
 		OperationIndex: transfer.Transfer.Origin,
 		Signature:      transfer.Signature,
 		Origin:         transfer.Origin,
+		ToChain:        transfer.Transfer.To.Chain,
 		MerklePath:     make([]string, 0, len(transfer.MerklePath)),
 		RetriesLeft:    maxRetries,
+		TraceContext:   tracing.Inject(ctx),
 	}
 
 	for _, hash := range transfer.MerklePath {
@@ -0,0 +1,88 @@
+package failover
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ErrCircuitOpen is returned without calling the endpoints while all the healthy ones keep failing the calls
+var ErrCircuitOpen = errors.New("endpoint circuit breakers are open")
+
+// BreakerSettings configures the circuit breakers of the pool endpoints
+type BreakerSettings struct {
+	// MinRequests is the number of the calls in the interval required before the breaker can open
+	MinRequests uint32
+	// FailureRatio opens the breaker when the share of the failed calls in the interval reaches it
+	FailureRatio float64
+	// Interval is the period the failures are counted in while the breaker is closed
+	Interval time.Duration
+	// OpenTimeout is the period after which the open breaker lets the probe calls through
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of successful probe calls required to close the breaker
+	HalfOpenRequests uint32
+	// IsSuccessful tells the errors that prove the endpoint is working, e.g. the reverted call
+	IsSuccessful func(err error) bool
+	// OnStateChange is called with the index of the endpoint whose breaker changed the state
+	OnStateChange func(endpoint int, from, to gobreaker.State)
+}
+
+// SetBreakers opens the endpoint that keeps failing the calls, so the pool fails over to the next endpoint
+// without waiting for the health check, and the open one is probed again after the open timeout
+func (p *Pool[T]) SetBreakers(settings BreakerSettings) {
+	for _, n := range p.nodes {
+		index := n.index
+		breaker := gobreaker.NewTwoStepCircuitBreaker(gobreaker.Settings{
+			Name:        fmt.Sprintf("%s/%d", p.name, index),
+			MaxRequests: settings.HalfOpenRequests,
+			Interval:    settings.Interval,
+			Timeout:     settings.OpenTimeout,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.Requests >= settings.MinRequests &&
+					float64(counts.TotalFailures)/float64(counts.Requests) >= settings.FailureRatio
+			},
+			OnStateChange: func(_ string, from, to gobreaker.State) {
+				if settings.OnStateChange != nil {
+					settings.OnStateChange(index, from, to)
+				}
+			},
+		})
+
+		n.mu.Lock()
+		n.breaker = breaker
+		n.isSuccessful = settings.IsSuccessful
+		n.mu.Unlock()
+	}
+}
+
+// allow lets the call through the breaker of the endpoint, the returned done reports the outcome of the call
+func (n *node[T]) allow() (func(err error), error) {
+	n.mu.RLock()
+	breaker, isSuccessful := n.breaker, n.isSuccessful
+	n.mu.RUnlock()
+
+	if breaker == nil {
+		return func(error) {}, nil
+	}
+
+	report, err := breaker.Allow()
+	if err != nil {
+		return nil, errors.Wrap(ErrCircuitOpen, err.Error(), logan.F{"endpoint": n.index})
+	}
+
+	return func(err error) {
+		report(err == nil || isSuccessful != nil && isSuccessful(err))
+	}, nil
+}
+
+// open tells whether the breaker of the endpoint does not let the calls through
+func (n *node[T]) open() bool {
+	n.mu.RLock()
+	breaker := n.breaker
+	n.mu.RUnlock()
+
+	return breaker != nil && breaker.State() == gobreaker.StateOpen
+}
@@ -0,0 +1,107 @@
+package failover
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func TestBreakers(t *testing.T) {
+	callErr := errors.New("call failed")
+	revertErr := errors.New("call reverted")
+
+	cases := []struct {
+		name    string
+		clients []fakeClient
+		// calls is the number of the calls made through Do before the client is acquired
+		calls   int
+		want    string
+		wantErr error
+		// wantOpen are the endpoints whose breakers are expected to be open
+		wantOpen []int
+	}{
+		{
+			name:    "closed breakers",
+			clients: []fakeClient{{result: "a"}, {result: "b"}},
+			calls:   3,
+			want:    "a",
+		},
+		{
+			name:     "failing endpoint is skipped",
+			clients:  []fakeClient{{result: "a", err: callErr}, {result: "b"}},
+			calls:    2,
+			want:     "b",
+			wantOpen: []int{0},
+		},
+		{
+			name:    "successful errors keep the breaker closed",
+			clients: []fakeClient{{result: "a", err: revertErr}, {result: "b"}},
+			calls:   3,
+			want:    "a",
+		},
+		{
+			name:     "all endpoints failing",
+			clients:  []fakeClient{{result: "a", err: callErr}, {result: "b", err: callErr}},
+			calls:    2,
+			wantErr:  ErrCircuitOpen,
+			wantOpen: []int{0, 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := newFakePool(t, tc.clients)
+
+			changes := make(map[int]gobreaker.State)
+			pool.SetBreakers(BreakerSettings{
+				MinRequests:      2,
+				FailureRatio:     0.5,
+				Interval:         time.Minute,
+				OpenTimeout:      time.Minute,
+				HalfOpenRequests: 1,
+				IsSuccessful: func(err error) bool {
+					return err == revertErr
+				},
+				OnStateChange: func(endpoint int, _, to gobreaker.State) {
+					changes[endpoint] = to
+				},
+			})
+
+			for i := 0; i < tc.calls; i++ {
+				_ = pool.Do(func(client fakeClient) error {
+					return client.err
+				})
+			}
+
+			client, done, err := pool.Acquire()
+			if tc.wantErr != nil {
+				if errors.Cause(err) != tc.wantErr {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				done(nil)
+				if client.result != tc.want {
+					t.Fatalf("expected %q, got %q", tc.want, client.result)
+				}
+			}
+
+			for endpoint := range tc.clients {
+				wantOpen := false
+				for _, open := range tc.wantOpen {
+					wantOpen = wantOpen || open == endpoint
+				}
+				if pool.nodes[endpoint].open() != wantOpen {
+					t.Fatalf("expected endpoint %d open %v", endpoint, wantOpen)
+				}
+				if wantOpen && changes[endpoint] != gobreaker.StateOpen {
+					t.Fatalf("expected the state change of endpoint %d to be reported", endpoint)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package failover
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/metrics"
+)
+
+const (
+	DefaultCheckInterval = 15 * time.Second
+	checkTimeout         = 10 * time.Second
+	// closeDelay is the time the requests in flight are given to finish on the dropped client
+	closeDelay = time.Minute
+)
+
+var ErrNoHealthyEndpoints = errors.New("no healthy endpoints")
+
+// DialFunc creates the client for the endpoint
+type DialFunc[T any] func(url string) (T, error)
+
+// CheckFunc returns an error if the endpoint behind the client is not able to serve requests
+type CheckFunc[T any] func(ctx context.Context, client T) error
+
+// Pool keeps the clients of the ordered endpoints of one chain, serving requests with the first
+// healthy one and failing over to the next ones when it goes down
+type Pool[T any] struct {
+	name  string
+	dial  DialFunc[T]
+	check CheckFunc[T]
+	nodes []*node[T]
+}
+
+type node[T any] struct {
+	mu      sync.RWMutex
+	index   int
+	url     string
+	client  T
+	dialed  bool
+	healthy bool
+	// breaker is set only for the pools the calls through which are reported
+	breaker      *gobreaker.TwoStepCircuitBreaker
+	isSuccessful func(err error) bool
+}
+
+func NewPool[T any](name string, urls []string, dial DialFunc[T], check CheckFunc[T]) (*Pool[T], error) {
+	if len(urls) == 0 {
+		return nil, errors.From(errors.New("at least one endpoint is required"), logan.F{"pool": name})
+	}
+
+	p := &Pool[T]{
+		name:  name,
+		dial:  dial,
+		check: check,
+		nodes: make([]*node[T], len(urls)),
+	}
+	for i, url := range urls {
+		// every endpoint is considered healthy until the check proves otherwise
+		p.nodes[i] = &node[T]{index: i, url: url, healthy: true}
+	}
+
+	return p, nil
+}
+
+// Name returns the name the pool reports its endpoints under
+func (p *Pool[T]) Name() string {
+	return p.name
+}
+
+// Len returns the number of configured endpoints
+func (p *Pool[T]) Len() int {
+	return len(p.nodes)
+}
+
+// Acquire returns the client of the first healthy endpoint whose breaker lets the call through, the outcome of
+// the calls made with the client has to be reported with done, so the breaker of the endpoint counts it
+func (p *Pool[T]) Acquire() (T, func(err error), error) {
+	err := errors.From(ErrNoHealthyEndpoints, logan.F{"pool": p.name})
+	for _, n := range p.nodes {
+		client, healthyErr := p.healthyClient(n)
+		if healthyErr != nil {
+			continue
+		}
+
+		done, allowErr := n.allow()
+		if allowErr != nil {
+			err = errors.From(allowErr, logan.F{"pool": p.name})
+			continue
+		}
+
+		return client, done, nil
+	}
+
+	var empty T
+	return empty, nil, err
+}
+
+// Clients returns the clients of all the healthy endpoints whose breakers are not open in the configured order
+func (p *Pool[T]) Clients() []T {
+	clients := make([]T, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		if n.open() {
+			continue
+		}
+
+		if client, err := p.healthyClient(n); err == nil {
+			clients = append(clients, client)
+		}
+	}
+
+	return clients
+}
+
+// Do calls fn with the healthy clients in order until it succeeds, returning the last error otherwise. The
+// endpoints whose breakers are open are skipped. It must be used only for the calls that are safe to repeat
+// on the other endpoint.
+func (p *Pool[T]) Do(fn func(client T) error) error {
+	err := errors.From(ErrNoHealthyEndpoints, logan.F{"pool": p.name})
+	called := false
+	for _, n := range p.nodes {
+		client, healthyErr := p.healthyClient(n)
+		if healthyErr != nil {
+			continue
+		}
+
+		done, allowErr := n.allow()
+		if allowErr != nil {
+			// the error of the endpoint that was called is more telling than the open breaker
+			if !called {
+				err = errors.From(allowErr, logan.F{"pool": p.name})
+			}
+			continue
+		}
+
+		called = true
+		err = fn(client)
+		done(err)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Available tells whether at least one endpoint passed the last health check
+func (p *Pool[T]) Available() bool {
+	for _, n := range p.nodes {
+		n.mu.RLock()
+		healthy := n.healthy
+		n.mu.RUnlock()
+
+		if healthy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check checks the health of every endpoint, dialing again the ones that failed
+func (p *Pool[T]) Check(ctx context.Context, log *logan.Entry) {
+	log = log.WithField("pool", p.name)
+	for _, n := range p.nodes {
+		err := p.checkNode(ctx, n)
+		if err != nil {
+			// dropping the client so the endpoint is dialed again on the next check
+			p.reset(n)
+		}
+
+		n.mu.Lock()
+		wasHealthy := n.healthy
+		n.healthy = err == nil
+		n.mu.Unlock()
+
+		// endpoints are identified by index as their URLs may contain API keys
+		nodeLog := log.WithField("endpoint", n.index)
+		switch {
+		case err != nil && wasHealthy:
+			nodeLog.WithError(err).Warn("endpoint became unhealthy")
+		case err == nil && !wasHealthy:
+			nodeLog.Info("endpoint recovered")
+		}
+		metrics.EndpointUp.WithLabelValues(p.name, strconv.Itoa(n.index)).Set(metrics.BoolToFloat(err == nil))
+	}
+}
+
+func (p *Pool[T]) checkNode(ctx context.Context, n *node[T]) error {
+	client, err := p.dialNode(n)
+	if err != nil {
+		return errors.Wrap(err, "failed to dial the endpoint")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	return p.check(ctx, client)
+}
+
+func (p *Pool[T]) healthyClient(n *node[T]) (T, error) {
+	n.mu.RLock()
+	healthy := n.healthy
+	n.mu.RUnlock()
+
+	if !healthy {
+		var empty T
+		return empty, errors.New("endpoint is unhealthy")
+	}
+
+	client, err := p.dialNode(n)
+	if err != nil {
+		n.mu.Lock()
+		n.healthy = false
+		n.mu.Unlock()
+	}
+
+	return client, err
+}
+
+func (p *Pool[T]) dialNode(n *node[T]) (T, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.dialed {
+		return n.client, nil
+	}
+
+	client, err := p.dial(n.url)
+	if err != nil {
+		return client, err
+	}
+
+	n.client = client
+	n.dialed = true
+
+	return client, nil
+}
+
+func (p *Pool[T]) reset(n *node[T]) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.dialed {
+		return
+	}
+
+	// the client may still be used by the callers that got it before the check failed
+	if closer, ok := any(n.client).(interface{ Close() }); ok {
+		time.AfterFunc(closeDelay, closer.Close)
+	}
+
+	var empty T
+	n.client = empty
+	n.dialed = false
+}
@@ -0,0 +1,76 @@
+package failover
+
+import (
+	"context"
+	"sync"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+var ErrNoQuorum = errors.New("endpoints did not reach the quorum")
+
+// Quorum calls fn on all the healthy endpoints concurrently and returns the result that at least
+// quorum of them agreed on. Quorum less than two falls back to the first successful response. While
+// fewer than quorum endpoints are healthy, all the healthy ones have to agree, so a single provider
+// outage does not stop the calls.
+func Quorum[T any, R comparable](ctx context.Context, pool *Pool[T], quorum int, fn func(ctx context.Context, client T) (R, error)) (R, error) {
+	var empty R
+
+	if quorum < 2 {
+		var result R
+		err := pool.Do(func(client T) (err error) {
+			result, err = fn(ctx, client)
+			return err
+		})
+
+		return result, err
+	}
+
+	clients := pool.Clients()
+	if len(clients) == 0 {
+		return empty, errors.From(ErrNoHealthyEndpoints, logan.F{"pool": pool.Name()})
+	}
+	if len(clients) < quorum {
+		quorum = len(clients)
+	}
+
+	type response struct {
+		result R
+		err    error
+	}
+
+	responses := make([]response, len(clients))
+
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client T) {
+			defer wg.Done()
+			result, err := fn(ctx, client)
+			responses[i] = response{result: result, err: err}
+		}(i, client)
+	}
+	wg.Wait()
+
+	votes := make(map[R]int)
+	var lastErr error
+	for _, resp := range responses {
+		if resp.err != nil {
+			lastErr = resp.err
+			continue
+		}
+
+		votes[resp.result]++
+		if votes[resp.result] >= quorum {
+			return resp.result, nil
+		}
+	}
+
+	fields := logan.F{"pool": pool.Name(), "quorum": quorum}
+	if lastErr != nil {
+		fields["last_error"] = lastErr.Error()
+	}
+
+	return empty, errors.From(ErrNoQuorum, fields)
+}
@@ -0,0 +1,118 @@
+package failover
+
+import (
+	"context"
+	"testing"
+
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type fakeClient struct {
+	result string
+	err    error
+}
+
+func newFakePool(t *testing.T, clients []fakeClient, unhealthy ...int) *Pool[fakeClient] {
+	urls := make([]string, len(clients))
+	byURL := make(map[string]fakeClient, len(clients))
+	for i, client := range clients {
+		urls[i] = string(rune('a' + i))
+		byURL[urls[i]] = client
+	}
+
+	pool, err := NewPool("test", urls, func(url string) (fakeClient, error) {
+		return byURL[url], nil
+	}, func(context.Context, fakeClient) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range unhealthy {
+		pool.nodes[i].healthy = false
+	}
+
+	return pool
+}
+
+func TestQuorum(t *testing.T) {
+	callErr := errors.New("call failed")
+
+	cases := []struct {
+		name      string
+		clients   []fakeClient
+		unhealthy []int
+		quorum    int
+		want      string
+		wantErr   error
+	}{
+		{
+			name:    "first response without quorum",
+			clients: []fakeClient{{err: callErr}, {result: "b"}},
+			quorum:  1,
+			want:    "b",
+		},
+		{
+			name:    "agreed result",
+			clients: []fakeClient{{result: "a"}, {result: "b"}, {result: "a"}},
+			quorum:  2,
+			want:    "a",
+		},
+		{
+			name:    "disagreement",
+			clients: []fakeClient{{result: "a"}, {result: "b"}},
+			quorum:  2,
+			wantErr: ErrNoQuorum,
+		},
+		{
+			name:    "failed call",
+			clients: []fakeClient{{result: "a"}, {err: callErr}},
+			quorum:  2,
+			wantErr: ErrNoQuorum,
+		},
+		{
+			name:      "single endpoint outage",
+			clients:   []fakeClient{{result: "a"}, {result: "b"}},
+			unhealthy: []int{1},
+			quorum:    2,
+			want:      "a",
+		},
+		{
+			name:      "healthy endpoints disagree",
+			clients:   []fakeClient{{result: "a"}, {result: "b"}, {result: "c"}},
+			unhealthy: []int{2},
+			quorum:    3,
+			wantErr:   ErrNoQuorum,
+		},
+		{
+			name:      "all endpoints down",
+			clients:   []fakeClient{{result: "a"}, {result: "a"}},
+			unhealthy: []int{0, 1},
+			quorum:    2,
+			wantErr:   ErrNoHealthyEndpoints,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := newFakePool(t, tc.clients, tc.unhealthy...)
+
+			got, err := Quorum(context.Background(), pool, tc.quorum, func(_ context.Context, client fakeClient) (string, error) {
+				return client.result, client.err
+			})
+			if tc.wantErr != nil {
+				if errors.Cause(err) != tc.wantErr {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "relayer"
+
+var (
+	SignerBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "signer_balance",
+		Help:      "Native balance of the withdrawal signer in the smallest units of the chain",
+	}, []string{"chain", "signer"})
+
+	ChainPaused = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_paused",
+		Help:      "Whether relaying to the chain is paused (1) or not (0)",
+	}, []string{"chain"})
+
+	CircuitBreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_state",
+		Help:      "State of the chain endpoint circuit breaker: closed (0), half-open (1) or open (2)",
+	}, []string{"chain", "endpoint"})
+
+	ChainAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_available",
+		Help:      "Whether the chain has at least one healthy endpoint (1) or not (0)",
+	}, []string{"chain"})
+
+	DeadLetteredTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_lettered_tasks_total",
+		Help:      "Number of the relay tasks moved to the dead letter list",
+	}, []string{"chain"})
+
+	WithdrawalMismatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "withdrawal_mismatches_total",
+		Help:      "Number of the included withdrawals that did not pay the transfer out as requested",
+	}, []string{"chain"})
+
+	ConfigMismatch = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "config_mismatch",
+		Help:      "Whether the local chain config disagrees with the core params (1) or not (0)",
+	}, []string{"chain", "field"})
+
+	EndpointUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "endpoint_up",
+		Help:      "Whether the chain endpoint passed the last health check (1) or not (0)",
+	}, []string{"pool", "endpoint"})
+
+	VaultUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "vault_up",
+		Help:      "Whether the last Vault token renewal and secret reload succeeded (1) or not (0)",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		SignerBalance,
+		ChainPaused,
+		CircuitBreakerState,
+		ChainAvailable,
+		DeadLetteredTasks,
+		WithdrawalMismatches,
+		ConfigMismatch,
+		EndpointUp,
+		VaultUp,
+	)
+}
+
+// BigToFloat converts the amount to the gauge value, precision loss is acceptable for monitoring
+func BigToFloat(amount *big.Int) float64 {
+	value, _ := new(big.Float).SetInt(amount).Float64()
+	return value
+}
+
+// BoolToFloat converts the flag to the gauge value
+func BoolToFloat(flag bool) float64 {
+	if flag {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,60 @@
+package nonce
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type local struct {
+	gapTimeout time.Duration
+
+	mu     sync.Mutex
+	states map[string]*state
+}
+
+// NewLocal returns the allocator keeping the state in memory, it is suitable for the single replica only
+func NewLocal(gapTimeout time.Duration) Allocator {
+	return &local{
+		gapTimeout: gapTimeout,
+		states:     make(map[string]*state),
+	}
+}
+
+func (l *local) Next(ctx context.Context, account string, pending PendingFunc) (uint64, error) {
+	expected, err := pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.state(account).allocate(expected, time.Now(), l.gapTimeout), nil
+}
+
+func (l *local) Release(_ context.Context, account string, nonce uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state(account).release(nonce)
+	return nil
+}
+
+func (l *local) Resync(_ context.Context, account string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.states, account)
+	return nil
+}
+
+func (l *local) state(account string) *state {
+	s, ok := l.states[account]
+	if !ok {
+		s = new(state)
+		l.states[account] = s
+	}
+
+	return s
+}
@@ -0,0 +1,49 @@
+package nonce
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	BackendLocal = "local"
+	BackendRedis = "redis"
+
+	// DefaultGapTimeout is the period the node may not know the transaction with the handed out nonce
+	// before the nonce is considered dropped and handed out again
+	DefaultGapTimeout = 2 * time.Minute
+)
+
+// PendingFunc returns the nonce the node expects for the next transaction of the account,
+// including the transactions in its mempool
+type PendingFunc func(ctx context.Context) (uint64, error)
+
+// Allocator hands out sequential nonces for the accounts, so the concurrent submissions from
+// the same signer do not collide. The account is an arbitrary key, usually the chain with the signer address.
+type Allocator interface {
+	// Next reserves the nonce for the next transaction of the account
+	Next(ctx context.Context, account string, pending PendingFunc) (uint64, error)
+	// Release returns the nonce of the transaction that never reached the node, so it is handed out again
+	Release(ctx context.Context, account string, nonce uint64) error
+	// Resync drops the account state, so the next nonce is taken from the node
+	Resync(ctx context.Context, account string) error
+}
+
+// Account returns the allocator account of the signer key in the chain
+func Account(chain, signer string) string {
+	return fmt.Sprintf("%s:%s", chain, signer)
+}
+
+// Settle returns the nonce of the transaction that did not reach the node to the allocator, the account is resynced
+// with the node instead when the nonce was rejected by it. Nothing is done for the sent transaction.
+func Settle(ctx context.Context, allocator Allocator, account string, nonce uint64, sent, rejected bool) error {
+	switch {
+	case rejected:
+		return allocator.Resync(ctx, account)
+	case !sent:
+		return allocator.Release(ctx, account, nonce)
+	default:
+		return nil
+	}
+}
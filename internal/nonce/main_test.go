@@ -0,0 +1,54 @@
+package nonce
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSettle(t *testing.T) {
+	const account = "near:ed25519:signer"
+
+	cases := []struct {
+		name     string
+		sent     bool
+		rejected bool
+		// pending is the nonce the node expects after the settlement
+		pending uint64
+		want    uint64
+	}{
+		{name: "sent nonce is kept", sent: true, pending: 5, want: 7},
+		{name: "unsent nonce is handed out again", pending: 5, want: 6},
+		{name: "rejected nonce resyncs the account", rejected: true, pending: 9, want: 9},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			allocator := NewLocal(time.Minute)
+			pending := func(context.Context) (uint64, error) { return 5, nil }
+
+			for _, want := range []uint64{5, 6} {
+				got, err := allocator.Next(ctx, account, pending)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Fatalf("got nonce %d, want %d", got, want)
+				}
+			}
+
+			if err := Settle(ctx, allocator, account, 6, tc.sent, tc.rejected); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := allocator.Next(ctx, account, func(context.Context) (uint64, error) { return tc.pending, nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("got nonce %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package nonce
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	redisKeyPrefix = "nonce:"
+	// maxTxAttempts bounds the optimistic transaction retries when the replicas update the same account
+	maxTxAttempts = 10
+)
+
+type redisAllocator struct {
+	client     *redis.Client
+	gapTimeout time.Duration
+}
+
+// NewRedis returns the allocator keeping the state in redis, so the nonces are shared by all the replicas
+func NewRedis(client *redis.Client, gapTimeout time.Duration) Allocator {
+	return &redisAllocator{
+		client:     client,
+		gapTimeout: gapTimeout,
+	}
+}
+
+func (r *redisAllocator) Next(ctx context.Context, account string, pending PendingFunc) (uint64, error) {
+	expected, err := pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var nonce uint64
+	err = r.update(ctx, account, func(s *state) {
+		nonce = s.allocate(expected, time.Now(), r.gapTimeout)
+	})
+
+	return nonce, err
+}
+
+func (r *redisAllocator) Release(ctx context.Context, account string, nonce uint64) error {
+	return r.update(ctx, account, func(s *state) {
+		s.release(nonce)
+	})
+}
+
+func (r *redisAllocator) Resync(ctx context.Context, account string) error {
+	if err := r.client.Del(ctx, redisKeyPrefix+account).Err(); err != nil {
+		return errors.Wrap(err, "failed to drop the nonce state", logan.F{"account": account})
+	}
+
+	return nil
+}
+
+// update applies fn to the account state atomically, retrying when the state was changed concurrently
+func (r *redisAllocator) update(ctx context.Context, account string, fn func(s *state)) error {
+	key := redisKeyPrefix + account
+
+	txf := func(tx *redis.Tx) error {
+		var s state
+
+		raw, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case err == redis.Nil:
+		case err != nil:
+			return errors.Wrap(err, "failed to get the nonce state")
+		default:
+			if err = json.Unmarshal(raw, &s); err != nil {
+				return errors.Wrap(err, "failed to unmarshal the nonce state")
+			}
+		}
+
+		fn(&s)
+
+		raw, err = json.Marshal(s)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the nonce state")
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, raw, 0)
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < maxTxAttempts; i++ {
+		err := r.client.Watch(ctx, txf, key)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to update the nonce state", logan.F{"account": account})
+		}
+
+		return nil
+	}
+
+	return errors.From(errors.New("nonce state is updated concurrently too often"), logan.F{"account": account})
+}
@@ -0,0 +1,74 @@
+package nonce
+
+import (
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// state is the allocation state of one account, it is shared by the allocator backends
+type state struct {
+	Synced bool   `json:"synced"`
+	Next   uint64 `json:"next"`
+	// Released are the nonces returned by the failed submissions, they are handed out first
+	Released []uint64 `json:"released,omitempty"`
+	// Used are the handed out nonces mapped to the unix time they were handed out at
+	Used map[uint64]int64 `json:"used,omitempty"`
+}
+
+// allocate returns the next nonce given the one the node expects
+func (s *state) allocate(pending uint64, now time.Time, gapTimeout time.Duration) uint64 {
+	s.forget(pending)
+
+	if !s.Synced || pending > s.Next {
+		// the state is either new or behind the transactions sent bypassing the allocator
+		s.Next = pending
+		s.Synced = true
+	}
+
+	if s.Used == nil {
+		s.Used = make(map[uint64]int64)
+	}
+
+	// the node expects the nonce handed out long ago, so the transaction was dropped and
+	// all the following ones are stuck until the gap is filled
+	if usedAt, ok := s.Used[pending]; ok && pending < s.Next && now.Sub(time.Unix(usedAt, 0)) >= gapTimeout {
+		s.Used[pending] = now.Unix()
+		return pending
+	}
+
+	nonce := s.Next
+	if len(s.Released) > 0 {
+		nonce, s.Released = s.Released[0], s.Released[1:]
+	} else {
+		s.Next++
+	}
+
+	s.Used[nonce] = now.Unix()
+	return nonce
+}
+
+// release makes the nonce available for the next allocation
+func (s *state) release(nonce uint64) {
+	delete(s.Used, nonce)
+	if nonce >= s.Next {
+		return
+	}
+
+	i, found := slices.BinarySearch(s.Released, nonce)
+	if !found {
+		s.Released = slices.Insert(s.Released, i, nonce)
+	}
+}
+
+// forget drops the nonces the node already has transactions with
+func (s *state) forget(pending uint64) {
+	for nonce := range s.Used {
+		if nonce < pending {
+			delete(s.Used, nonce)
+		}
+	}
+
+	i, _ := slices.BinarySearch(s.Released, pending)
+	s.Released = s.Released[i:]
+}
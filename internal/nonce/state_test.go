@@ -0,0 +1,164 @@
+package nonce
+
+import (
+	"testing"
+	"time"
+)
+
+// step either allocates the nonce given the pending one or releases the nonce
+type step struct {
+	release bool
+	pending uint64
+	nonce   uint64
+	// after is the time passed since the start of the case
+	after time.Duration
+	want  uint64
+}
+
+func allocate(pending, want uint64) step {
+	return step{pending: pending, want: want}
+}
+
+func release(nonce uint64) step {
+	return step{release: true, nonce: nonce}
+}
+
+func TestStateAllocate(t *testing.T) {
+	const gapTimeout = time.Minute
+
+	cases := []struct {
+		name  string
+		steps []step
+		// wantNext and wantReleased describe the state after the steps
+		wantNext     uint64
+		wantReleased []uint64
+	}{
+		{
+			name:     "new state starts from the pending nonce",
+			steps:    []step{allocate(7, 7), allocate(7, 8), allocate(7, 9)},
+			wantNext: 10,
+		},
+		{
+			name:     "state catches up with the transactions sent bypassing the allocator",
+			steps:    []step{allocate(3, 3), allocate(10, 10)},
+			wantNext: 11,
+		},
+		{
+			name:     "released nonce is handed out first",
+			steps:    []step{allocate(0, 0), allocate(0, 1), allocate(0, 2), release(1), allocate(0, 1), allocate(0, 3)},
+			wantNext: 4,
+		},
+		{
+			name:         "released nonces are kept sorted and deduplicated",
+			steps:        []step{allocate(0, 0), allocate(0, 1), allocate(0, 2), release(2), release(0), release(2)},
+			wantNext:     3,
+			wantReleased: []uint64{0, 2},
+		},
+		{
+			name:     "releasing the nonce not handed out yet is ignored",
+			steps:    []step{allocate(0, 0), release(5), allocate(0, 1)},
+			wantNext: 2,
+		},
+		{
+			name:         "released nonces the node already has are forgotten",
+			steps:        []step{allocate(0, 0), allocate(0, 1), allocate(0, 2), release(0), release(2), allocate(1, 2)},
+			wantNext:     3,
+			wantReleased: []uint64{},
+		},
+		{
+			name:     "dropped nonce is handed out again after the gap timeout",
+			steps:    []step{allocate(0, 0), allocate(0, 1), {pending: 0, after: gapTimeout, want: 0}, allocate(0, 2)},
+			wantNext: 3,
+		},
+		{
+			name:     "dropped nonce is not handed out again within the gap timeout",
+			steps:    []step{allocate(0, 0), allocate(0, 1), {pending: 0, after: gapTimeout / 2, want: 2}},
+			wantNext: 3,
+		},
+	}
+
+	start := time.Unix(1700000000, 0)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var s state
+			for i, step := range tc.steps {
+				if step.release {
+					s.release(step.nonce)
+					continue
+				}
+
+				if got := s.allocate(step.pending, start.Add(step.after), gapTimeout); got != step.want {
+					t.Fatalf("step %d: got nonce %d, want %d", i, got, step.want)
+				}
+			}
+
+			if s.Next != tc.wantNext {
+				t.Fatalf("got next nonce %d, want %d", s.Next, tc.wantNext)
+			}
+			if len(s.Released) != len(tc.wantReleased) {
+				t.Fatalf("got released %v, want %v", s.Released, tc.wantReleased)
+			}
+			for i := range tc.wantReleased {
+				if s.Released[i] != tc.wantReleased[i] {
+					t.Fatalf("got released %v, want %v", s.Released, tc.wantReleased)
+				}
+			}
+		})
+	}
+}
+
+func TestStateForget(t *testing.T) {
+	cases := []struct {
+		name         string
+		used         []uint64
+		released     []uint64
+		pending      uint64
+		wantUsed     []uint64
+		wantReleased []uint64
+	}{
+		{
+			name:         "nothing is known to the node",
+			used:         []uint64{5, 6},
+			released:     []uint64{4},
+			pending:      4,
+			wantUsed:     []uint64{5, 6},
+			wantReleased: []uint64{4},
+		},
+		{
+			name:         "mined nonces are dropped",
+			used:         []uint64{3, 5, 6},
+			released:     []uint64{2, 4, 7},
+			pending:      5,
+			wantUsed:     []uint64{5, 6},
+			wantReleased: []uint64{7},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := state{Released: tc.released, Used: make(map[uint64]int64)}
+			for _, nonce := range tc.used {
+				s.Used[nonce] = 0
+			}
+
+			s.forget(tc.pending)
+
+			if len(s.Used) != len(tc.wantUsed) {
+				t.Fatalf("got used %v, want %v", s.Used, tc.wantUsed)
+			}
+			for _, nonce := range tc.wantUsed {
+				if _, ok := s.Used[nonce]; !ok {
+					t.Fatalf("got used %v, want %v", s.Used, tc.wantUsed)
+				}
+			}
+			if len(s.Released) != len(tc.wantReleased) {
+				t.Fatalf("got released %v, want %v", s.Released, tc.wantReleased)
+			}
+			for i := range tc.wantReleased {
+				if s.Released[i] != tc.wantReleased[i] {
+					t.Fatalf("got released %v, want %v", s.Released, tc.wantReleased)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/google/jsonapi"
+	"gitlab.com/distributed_lab/ape"
+
+	"github.com/rarimo/relayer-svc/resources"
+)
+
+func GetHealth(w http.ResponseWriter, r *http.Request) {
+	cfg := Config(r)
+	healthy := true
+
+	paused, err := cfg.Redis().PausedChains(r.Context())
+	if err != nil {
+		Log(r).WithError(err).Error("failed to get paused chains")
+		healthy = false
+	}
+
+	breakers, err := cfg.Redis().BreakerStates(r.Context())
+	if err != nil {
+		Log(r).WithError(err).Error("failed to get circuit breaker states")
+		healthy = false
+	}
+
+	unavailable, err := cfg.Redis().UnavailableChains(r.Context())
+	if err != nil {
+		Log(r).WithError(err).Error("failed to get unavailable chains")
+		healthy = false
+	}
+
+	vault := resources.VaultHealth{Healthy: true}
+	vaultFailures, err := cfg.Redis().VaultFailures(r.Context())
+	if err != nil {
+		Log(r).WithError(err).Error("failed to get vault statuses")
+		healthy = false
+	}
+	for instance, failure := range vaultFailures {
+		healthy = false
+		vault.Healthy = false
+		vault.FailingInstances = append(vault.FailingInstances, resources.VaultFailure{
+			Instance:     instance,
+			Error:        failure.Error,
+			FailingSince: failure.FailingSince,
+		})
+	}
+	// the map order is random, while the response is expected to be stable between the requests
+	sort.Slice(vault.FailingInstances, func(i, j int) bool {
+		return vault.FailingInstances[i].Instance < vault.FailingInstances[j].Instance
+	})
+
+	chains := make([]resources.ChainHealth, 0)
+	for _, chain := range cfg.Chains().Names() {
+		status := resources.ChainHealth{Name: chain, Available: true}
+		if reason, ok := paused[chain]; ok {
+			reason := reason
+			status.Paused = true
+			status.PauseReason = &reason
+		}
+		if since, ok := unavailable[chain]; ok {
+			since := since
+			status.Available = false
+			status.UnavailableSince = &since
+		}
+		status.CircuitBreakers = breakers[chain]
+		drained, err := cfg.Redis().DrainedSigners(r.Context(), chain)
+		if err != nil {
+			Log(r).WithError(err).WithField("chain", chain).Error("failed to get drained signers")
+			healthy = false
+		}
+		if len(drained) != 0 {
+			status.DrainedSigners = drained
+		}
+
+		chains = append(chains, status)
+	}
+
+	if !healthy {
+		// ape.Render sets the content type after the status is written, so it has to be set here
+		w.Header().Set("content-type", jsonapi.MediaType)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	ape.Render(w, resources.HealthResponse{
+		Data: resources.Health{
+			Key: resources.Key{ID: "relayer", Type: resources.HEALTH},
+			Attributes: resources.HealthAttributes{
+				Healthy: healthy,
+				Chains:  chains,
+				Vault:   vault,
+			},
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+)
+
+func PostChainPause(w http.ResponseWriter, r *http.Request) {
+	chain, ok := chainFromPath(r)
+	if !ok {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := Config(r).Redis().PauseChain(r.Context(), chain, redis.PauseReasonManual); err != nil {
+		panic(errors.Wrap(err, "failed to pause the chain"))
+	}
+
+	Log(r).WithFields(logan.F{"chain": chain}).Warn("relaying to the chain is paused by the admin")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func PostChainResume(w http.ResponseWriter, r *http.Request) {
+	chain, ok := chainFromPath(r)
+	if !ok {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := Config(r).Redis().ResumeChain(r.Context(), chain); err != nil {
+		panic(errors.Wrap(err, "failed to resume the chain"))
+	}
+
+	Log(r).WithFields(logan.F{"chain": chain}).Info("relaying to the chain is resumed by the admin")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func chainFromPath(r *http.Request) (string, bool) {
+	chain := chi.URLParam(r, "chain")
+	return chain, Config(r).Chains().Has(chain)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/signer"
+)
+
+func PostSignerDrain(w http.ResponseWriter, r *http.Request) {
+	chain, address, ok := signerFromPath(r)
+	if !ok {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := Config(r).Redis().DrainSigner(r.Context(), chain, address, redis.DrainReasonManual); err != nil {
+		panic(errors.Wrap(err, "failed to drain the signer"))
+	}
+
+	Log(r).WithFields(logan.F{"chain": chain, "signer": address}).Warn("signer is drained by the admin")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func PostSignerUndrain(w http.ResponseWriter, r *http.Request) {
+	chain, address, ok := signerFromPath(r)
+	if !ok {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := Config(r).Redis().UndrainSigner(r.Context(), chain, address); err != nil {
+		panic(errors.Wrap(err, "failed to undrain the signer"))
+	}
+
+	Log(r).WithFields(logan.F{"chain": chain, "signer": address}).Info("signer is undrained by the admin")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// signerFromPath returns the chain and the key address of the signer in the path, it is not ok for the
+// address that is not among the keys signing for the chain, so a mistyped one is not stored as a drain
+func signerFromPath(r *http.Request) (string, string, bool) {
+	chain, ok := chainFromPath(r)
+	if !ok {
+		return "", "", false
+	}
+	family, ok := Config(r).Chains().Family(chain)
+	if !ok {
+		return "", "", false
+	}
+
+	key, err := signer.Lookup(r.Context(), Config(r).Signer(), chain, family, chi.URLParam(r, "signer"))
+	switch {
+	case errors.Cause(err) == signer.ErrUnknownKey:
+		return "", "", false
+	case err != nil:
+		panic(errors.Wrap(err, "failed to look up the signer", logan.F{"chain": chain}))
+	}
+
+	return chain, key.Address, true
+}
@@ -23,6 +23,13 @@ func (s *api) router() chi.Router {
 	r.Route("/relayer", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			r.Post("/relay_tasks", bouncer.RequestMiddleware(s.log, s.cfg.Bouncer(), handlers.PostRelayTask))
+			r.Get("/health", handlers.GetHealth)
+			r.Route("/chains/{chain}", func(r chi.Router) {
+				r.Post("/pause", bouncer.RequestMiddleware(s.log, s.cfg.Bouncer(), handlers.PostChainPause))
+				r.Post("/resume", bouncer.RequestMiddleware(s.log, s.cfg.Bouncer(), handlers.PostChainResume))
+				r.Post("/signers/{signer}/drain", bouncer.RequestMiddleware(s.log, s.cfg.Bouncer(), handlers.PostSignerDrain))
+				r.Post("/signers/{signer}/undrain", bouncer.RequestMiddleware(s.log, s.cfg.Bouncer(), handlers.PostSignerUndrain))
+			})
 		})
 	})
 
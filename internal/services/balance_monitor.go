@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/services/bridger"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+)
+
+type balanceMonitor struct {
+	log      *logan.Entry
+	cfg      *config.BalanceMonitorConfig
+	redis    redis.Rediser
+	bridgers bridger.BridgerProvider
+	chains   []string
+}
+
+func RunBalanceMonitor(cfg config.Config, ctx context.Context) {
+	log := cfg.Log().WithField("service", "balance_monitor")
+	if cfg.BalanceMonitor().Disabled {
+		log.Info("balance monitor is disabled")
+		return
+	}
+
+	m := balanceMonitor{
+		log:      log,
+		cfg:      cfg.BalanceMonitor(),
+		redis:    cfg.Redis(),
+		bridgers: bridger.NewBridgerProvider(cfg),
+		chains:   cfg.Chains().Names(),
+	}
+
+	interval := m.cfg.Interval
+	running.WithBackOff(ctx, log, "run_once", m.runOnce, interval, interval, 10*interval)
+}
+
+func (m *balanceMonitor) runOnce(ctx context.Context) error {
+	for _, chain := range m.chains {
+		if err := m.checkChain(ctx, chain); err != nil {
+			m.log.WithError(err).WithField("chain", chain).Error("failed to check the signer balance")
+		}
+	}
+
+	return nil
+}
+
+func (m *balanceMonitor) checkChain(ctx context.Context, chain string) error {
+	bridger, err := m.bridgers.GetBridger(chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the bridger")
+	}
+
+	balances, err := bridger.SignerBalances(ctx, chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the signer balances")
+	}
+
+	threshold, ok := m.cfg.Threshold(chain)
+
+	var best bridge.Balance
+	for _, balance := range balances {
+		metrics.SignerBalance.WithLabelValues(chain, balance.Signer).Set(metrics.BigToFloat(balance.Amount))
+		if best.Amount == nil || balance.Amount.Cmp(best.Amount) > 0 {
+			best = balance
+		}
+
+		// the single signer can not be drained, the chain is paused instead
+		if ok && len(balances) > 1 {
+			if err := m.checkSigner(ctx, chain, balance, threshold); err != nil {
+				m.log.WithError(err).WithFields(logan.F{
+					"chain":  chain,
+					"signer": balance.Signer,
+				}).Error("failed to check the signer balance")
+			}
+		}
+	}
+
+	if !ok || best.Amount == nil {
+		return nil
+	}
+
+	// relaying to the chain is paused only when none of the signers can pay for the withdrawals
+	log := m.log.WithFields(logan.F{
+		"chain":   chain,
+		"signer":  best.Signer,
+		"balance": best.Amount.String(),
+	})
+
+	reason, paused, err := m.redis.ChainPauseReason(ctx, chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the chain is paused")
+	}
+
+	switch {
+	case isBelow(best.Amount, threshold.Critical):
+		log.Error("signer balance is below the critical threshold")
+		if paused {
+			return nil
+		}
+
+		if err := m.redis.PauseChain(ctx, chain, redis.PauseReasonLowFunds); err != nil {
+			return errors.Wrap(err, "failed to pause the chain")
+		}
+		log.Warn("paused relaying to the chain due to low funds")
+	case isBelow(best.Amount, threshold.Warning):
+		log.Warn("signer balance is below the warning threshold")
+	default:
+		// resuming only when the balance is above the warning threshold, so the chain
+		// does not flap between paused and resumed states around the critical one
+		if !paused || reason != redis.PauseReasonLowFunds {
+			return nil
+		}
+
+		// the chain may be paused manually since the check, then it is left paused
+		resumed, err := m.redis.ResumeChainPausedFor(ctx, chain, redis.PauseReasonLowFunds)
+		if err != nil {
+			return errors.Wrap(err, "failed to resume the chain")
+		}
+		if !resumed {
+			return nil
+		}
+		log.Info("resumed relaying to the chain after the top up")
+	}
+
+	return nil
+}
+
+// checkSigner drains the signer of the pool running out of funds and brings it back after the top up
+func (m *balanceMonitor) checkSigner(ctx context.Context, chain string, balance bridge.Balance, threshold config.BalanceThreshold) error {
+	log := m.log.WithFields(logan.F{
+		"chain":   chain,
+		"signer":  balance.Signer,
+		"balance": balance.Amount.String(),
+	})
+
+	drained, err := m.redis.DrainedSigners(ctx, chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the drained signers")
+	}
+	reason, isDrained := drained[redis.NormalizeSigner(balance.Signer)]
+
+	switch {
+	case isBelow(balance.Amount, threshold.Critical):
+		if isDrained {
+			return nil
+		}
+
+		if err := m.redis.DrainSigner(ctx, chain, balance.Signer, redis.DrainReasonLowFunds); err != nil {
+			return errors.Wrap(err, "failed to drain the signer")
+		}
+		log.Warn("drained the signer due to low funds")
+	case isBelow(balance.Amount, threshold.Warning):
+		log.Warn("signer balance is below the warning threshold")
+	default:
+		if !isDrained || reason != redis.DrainReasonLowFunds {
+			return nil
+		}
+
+		// the signer may be drained manually since the check, then it is left drained
+		undrained, err := m.redis.UndrainSignerDrainedFor(ctx, chain, balance.Signer, redis.DrainReasonLowFunds)
+		if err != nil {
+			return errors.Wrap(err, "failed to undrain the signer")
+		}
+		if !undrained {
+			return nil
+		}
+		log.Info("signer is used again after the top up")
+	}
+
+	return nil
+}
+
+func isBelow(amount, threshold *big.Int) bool {
+	return threshold != nil && amount.Cmp(threshold) < 0
+}
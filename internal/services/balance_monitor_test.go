@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"gitlab.com/distributed_lab/logan/v3"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+// fakeDrains keeps the drained signers the way the redis storage does, under the normalized addresses
+type fakeDrains struct {
+	redis.Rediser
+	drained map[string]string
+	drains  int
+}
+
+func (f *fakeDrains) DrainedSigners(context.Context, string) (map[string]string, error) {
+	return f.drained, nil
+}
+
+func (f *fakeDrains) DrainSigner(_ context.Context, _, signer, reason string) error {
+	f.drains++
+	if _, ok := f.drained[redis.NormalizeSigner(signer)]; !ok || reason == redis.DrainReasonManual {
+		f.drained[redis.NormalizeSigner(signer)] = reason
+	}
+
+	return nil
+}
+
+func (f *fakeDrains) UndrainSignerDrainedFor(_ context.Context, _, signer, reason string) (bool, error) {
+	if f.drained[redis.NormalizeSigner(signer)] != reason {
+		return false, nil
+	}
+
+	delete(f.drained, redis.NormalizeSigner(signer))
+	return true, nil
+}
+
+func TestCheckSigner(t *testing.T) {
+	const (
+		chain    = "ethereum"
+		checksum = "0x52908400098527886E0F7030069857D2E4169EE7"
+	)
+	normalized := redis.NormalizeSigner(checksum)
+	threshold := config.BalanceThreshold{Chain: chain, Warning: big.NewInt(100), Critical: big.NewInt(10)}
+
+	cases := []struct {
+		name        string
+		drained     map[string]string
+		balance     int64
+		wantDrains  int
+		wantDrained map[string]string
+	}{
+		{
+			name:        "low funds drain",
+			balance:     5,
+			wantDrains:  1,
+			wantDrained: map[string]string{normalized: redis.DrainReasonLowFunds},
+		},
+		{
+			name:        "drained signer is not drained again",
+			drained:     map[string]string{normalized: redis.DrainReasonLowFunds},
+			balance:     5,
+			wantDrained: map[string]string{normalized: redis.DrainReasonLowFunds},
+		},
+		{
+			name:        "manual drain is kept on low funds",
+			drained:     map[string]string{normalized: redis.DrainReasonManual},
+			balance:     5,
+			wantDrained: map[string]string{normalized: redis.DrainReasonManual},
+		},
+		{
+			name:        "undrained after the top up",
+			drained:     map[string]string{normalized: redis.DrainReasonLowFunds},
+			balance:     500,
+			wantDrained: map[string]string{},
+		},
+		{
+			name:        "manual drain is kept after the top up",
+			drained:     map[string]string{normalized: redis.DrainReasonManual},
+			balance:     500,
+			wantDrained: map[string]string{normalized: redis.DrainReasonManual},
+		},
+		{
+			name:        "drain is kept below the warning threshold",
+			drained:     map[string]string{normalized: redis.DrainReasonLowFunds},
+			balance:     50,
+			wantDrained: map[string]string{normalized: redis.DrainReasonLowFunds},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			drains := &fakeDrains{drained: make(map[string]string)}
+			for signer, reason := range tc.drained {
+				drains.drained[signer] = reason
+			}
+
+			m := balanceMonitor{log: logan.New().Level(logan.ErrorLevel), redis: drains}
+			balance := bridge.Balance{Signer: checksum, Amount: big.NewInt(tc.balance)}
+			if err := m.checkSigner(context.Background(), chain, balance, threshold); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if drains.drains != tc.wantDrains {
+				t.Fatalf("got %d drains, want %d", drains.drains, tc.wantDrains)
+			}
+			if len(drains.drained) != len(tc.wantDrained) {
+				t.Fatalf("got drained %v, want %v", drains.drained, tc.wantDrained)
+			}
+			for signer, reason := range tc.wantDrained {
+				if drains.drained[signer] != reason {
+					t.Fatalf("got drained %v, want %v", drains.drained, tc.wantDrained)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package bridger
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/sony/gobreaker"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/failover"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+// rarimoEndpoint is the index the breaker of the single Rarimo endpoint is reported under
+const rarimoEndpoint = 0
+
+// breakers are shared by all the bridger providers of the process, so every consumer sees the same endpoint state
+var breakers = struct {
+	sync.Mutex
+	// pools are the names of the endpoint pools whose breakers are set
+	pools   map[string]bool
+	byChain map[string]*gobreaker.CircuitBreaker
+}{pools: make(map[string]bool), byChain: make(map[string]*gobreaker.CircuitBreaker)}
+
+type breakerBridger struct {
+	bridge.Bridger
+	breaker *gobreaker.CircuitBreaker
+}
+
+// withBreaker breaks the calls to every endpoint of the chain separately, so a single failing provider is
+// skipped by the failover while the rest of the endpoints keep serving the chain
+func withBreaker(cfg config.Config, chain string, family types.ChainFamily, bridger bridge.Bridger) bridge.Bridger {
+	if cfg.CircuitBreaker().Disabled {
+		return bridger
+	}
+
+	switch family {
+	case types.FamilyEVM:
+		if evmChain, ok := cfg.EVM().GetChainByName(chain); ok {
+			setBreakers(cfg, chain, evmChain.RPC)
+		}
+	case types.FamilySolana:
+		setBreakers(cfg, chain, cfg.Solana().RPC)
+	case types.FamilyNear:
+		setBreakers(cfg, chain, cfg.Near().RPC)
+	default:
+		// the Rarimo chain is served by the single endpoint, so its bridger is broken as a whole
+		return &breakerBridger{
+			Bridger: bridger,
+			breaker: getBreaker(cfg, chain),
+		}
+	}
+
+	return bridger
+}
+
+func setBreakers[T any](cfg config.Config, chain string, pool *failover.Pool[T]) {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	if breakers.pools[pool.Name()] {
+		return
+	}
+
+	log := cfg.Log().WithFields(logan.F{"service": "circuit_breaker", "chain": chain})
+	settings := cfg.CircuitBreaker()
+	pool.SetBreakers(failover.BreakerSettings{
+		MinRequests:      settings.MinRequests,
+		FailureRatio:     settings.FailureRatio,
+		Interval:         settings.Interval,
+		OpenTimeout:      settings.OpenTimeout,
+		HalfOpenRequests: settings.HalfOpenRequests,
+		IsSuccessful:     isChainHealthy,
+		OnStateChange: func(endpoint int, from, to gobreaker.State) {
+			// endpoints are identified by index as their URLs may contain API keys
+			log.WithFields(logan.F{"endpoint": endpoint, "from": from.String(), "to": to.String()}).
+				Warn("circuit breaker state changed")
+			reportBreakerState(cfg.Redis(), log, chain, endpoint, to)
+		},
+	})
+	for endpoint := 0; endpoint < pool.Len(); endpoint++ {
+		reportBreakerState(cfg.Redis(), log, chain, endpoint, gobreaker.StateClosed)
+	}
+	breakers.pools[pool.Name()] = true
+}
+
+func (b *breakerBridger) Submit(ctx context.Context, transfer core.TransferDetails) (*bridge.Submission, error) {
+	submission, err := b.breaker.Execute(func() (interface{}, error) {
+		return b.Bridger.Submit(ctx, transfer)
+	})
+	if err != nil {
+		return nil, b.breakerError(err)
+	}
+
+	return submission.(*bridge.Submission), nil
+}
+
+func (b *breakerBridger) Confirm(ctx context.Context, submission *bridge.Submission) (*bridge.Result, error) {
+	result, err := b.breaker.Execute(func() (interface{}, error) {
+		return b.Bridger.Confirm(ctx, submission)
+	})
+	if err != nil {
+		return nil, b.breakerError(err)
+	}
+
+	return result.(*bridge.Result), nil
+}
+
+func (b *breakerBridger) breakerError(err error) error {
+	if err == gobreaker.ErrOpenState || err == gobreaker.ErrTooManyRequests {
+		return errors.Wrap(bridge.ErrCircuitOpen, err.Error(), logan.F{"chain": b.breaker.Name()})
+	}
+
+	return err
+}
+
+func getBreaker(cfg config.Config, chain string) *gobreaker.CircuitBreaker {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	if breaker, ok := breakers.byChain[chain]; ok {
+		return breaker
+	}
+
+	log := cfg.Log().WithFields(logan.F{"service": "circuit_breaker", "chain": chain})
+	settings := cfg.CircuitBreaker()
+	breaker := gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        chain,
+		MaxRequests: settings.HalfOpenRequests,
+		Interval:    settings.Interval,
+		Timeout:     settings.OpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.Requests >= settings.MinRequests &&
+				float64(counts.TotalFailures)/float64(counts.Requests) >= settings.FailureRatio
+		},
+		IsSuccessful: isChainHealthy,
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			log.WithFields(logan.F{"from": from.String(), "to": to.String()}).Warn("circuit breaker state changed")
+			reportBreakerState(cfg.Redis(), log, name, rarimoEndpoint, to)
+		},
+	})
+	reportBreakerState(cfg.Redis(), log, chain, rarimoEndpoint, gobreaker.StateClosed)
+	breakers.byChain[chain] = breaker
+
+	return breaker
+}
+
+// isChainHealthy tells the outcomes that prove the chain RPC is working from the failures
+func isChainHealthy(err error) bool {
+	switch {
+	case err == nil, bridge.IsPermanent(err):
+		return true
+	default:
+		switch errors.Cause(err) {
+		case bridge.ErrAlreadyWithdrawn, bridge.ErrFeeTooHigh, bridge.ErrTxStuck, bridge.ErrInsufficientLiquidity,
+			bridge.ErrPending, bridge.ErrTxFailed, bridge.ErrTxDropped, bridge.ErrTxNotReady, signerpool.ErrAllDrained:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+func reportBreakerState(rediser redis.Rediser, log *logan.Entry, chain string, endpoint int, state gobreaker.State) {
+	metrics.CircuitBreakerState.WithLabelValues(chain, strconv.Itoa(endpoint)).Set(float64(state))
+	if err := rediser.SetBreakerState(context.Background(), chain, endpoint, state.String()); err != nil {
+		log.WithError(err).Error("failed to store the circuit breaker state")
+	}
+}
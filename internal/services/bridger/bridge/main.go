@@ -2,21 +2,153 @@ package bridge
 
 import (
 	"context"
-	"errors"
 	"math/big"
 	"time"
 
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/failover"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"golang.org/x/exp/slices"
 )
 
-var ErrAlreadyWithdrawn = errors.New("already withdrawn")
+var (
+	ErrAlreadyWithdrawn = errors.New("already withdrawn")
+	// ErrCircuitOpen is returned without calling the chain while all its healthy endpoints keep failing
+	ErrCircuitOpen = failover.ErrCircuitOpen
+	// ErrChainUnavailable is returned while none of the chain endpoints is healthy
+	ErrChainUnavailable = errors.New("chain is unavailable")
+	// ErrFeeTooHigh is returned while the chain fees exceed the configured cap
+	ErrFeeTooHigh = errors.New("chain fees are above the cap")
+	// ErrTxStuck is returned when the transaction is not mined after all the replacements
+	ErrTxStuck = errors.New("transaction is stuck")
+	// ErrPending is returned by Confirm while the submitted transaction is not final yet
+	ErrPending = errors.New("transaction is pending")
+	// ErrTxFailed is returned by Confirm when the transaction was included but failed for the unknown reason
+	ErrTxFailed = errors.New("transaction failed")
+	// ErrOutOfGas is returned by Confirm when the transaction ran out of gas, the compute units for Solana.
+	// It is not a property of the transfer, so the withdrawal is submitted again with the limit estimated anew.
+	ErrOutOfGas = errors.New("transaction ran out of gas")
+	// ErrTxDropped is returned by Confirm when the transaction can not be included anymore, so the
+	// withdrawal has to be submitted again
+	ErrTxDropped = errors.New("transaction was dropped")
+	// ErrUnknownChain is returned for the chains that are not configured, such tasks can never succeed
+	ErrUnknownChain = errors.New("unknown chain")
+	// ErrWithdrawReverted is returned when the withdrawal call reverts during the estimation or the simulation
+	// with the reason that is not known to the relayer
+	ErrWithdrawReverted = errors.New("withdrawal would revert")
+	// ErrInvalidSignature is returned when the bridge does not accept the transfer signature. The merkle proof
+	// is verified by recovering the signer from the root it leads to, so the invalid proof ends up here as well.
+	ErrInvalidSignature = errors.New("invalid transfer signature or merkle proof")
+	// ErrInvalidWithdrawParams is returned when the bridge rejects the transfer params, e.g. the zero receiver
+	ErrInvalidWithdrawParams = errors.New("invalid withdrawal params")
+	// ErrInsufficientLiquidity is returned when the bridge does not hold enough assets to pay the transfer out
+	ErrInsufficientLiquidity = errors.New("insufficient bridge liquidity")
+	// ErrWithdrawalMismatch is returned by Confirm when the included transaction did not withdraw the transfer
+	// as it was requested, the task is kept for the investigation instead of being withdrawn again
+	ErrWithdrawalMismatch = errors.New("withdrawal does not match the transfer")
+	// ErrTxTooLarge is returned when the withdrawal does not fit into the transaction of the chain, e.g. the
+	// merkle path of the transfer is too long
+	ErrTxTooLarge = errors.New("withdrawal transaction is too large")
+	// ErrTxNotReady is returned while the withdrawal can not be sent until the relayer prepares the chain state
+	// it depends on, e.g. the Solana lookup table the accounts are loaded from
+	ErrTxNotReady = errors.New("withdrawal transaction is not ready")
+)
+
+// transientErrors can not be fixed by retrying right away, but the task is likely to succeed later
+var transientErrors = []error{
+	ErrCircuitOpen,
+	ErrChainUnavailable,
+	ErrFeeTooHigh,
+	ErrTxStuck,
+	ErrOutOfGas,
+	ErrTxNotReady,
+	signerpool.ErrAllDrained,
+	ErrInsufficientLiquidity,
+	failover.ErrNoHealthyEndpoints,
+	// the endpoints disagree while one of them is lagging behind
+	failover.ErrNoQuorum,
+}
+
+// IsTransient checks whether the task has to be postponed instead of being failed
+func IsTransient(err error) bool {
+	return slices.Contains(transientErrors, errors.Cause(err))
+}
+
+// permanentErrors can not be fixed by retrying at all
+var permanentErrors = []error{
+	ErrUnknownChain,
+	ErrWithdrawReverted,
+	ErrInvalidSignature,
+	ErrInvalidWithdrawParams,
+	ErrWithdrawalMismatch,
+	ErrTxTooLarge,
+}
+
+// IsPermanent checks whether the task has to be dead-lettered instead of being retried
+func IsPermanent(err error) bool {
+	return slices.Contains(permanentErrors, errors.Cause(err))
+}
 
 type Bridger interface {
-	// Withdraw the asset in target chain
-	Withdraw(
+	// Submit sends the withdrawal transaction to the target chain without waiting for it to be included
+	Submit(
 		ctx context.Context,
 		transfer core.TransferDetails,
-	) error
+	) (*Submission, error)
+	// Confirm checks the submitted transaction, ErrPending is returned while it is not final. The submission
+	// may be updated, e.g. when the transaction is replaced, so it has to be stored again after the call.
+	Confirm(ctx context.Context, submission *Submission) (*Result, error)
+	// SignerBalances returns the native balances of the withdrawal signers pool in the target chain
+	SignerBalances(ctx context.Context, chain string) ([]Balance, error)
+}
+
+// Submission is the withdrawal transaction sent to the target chain
+type Submission struct {
+	Chain string `json:"chain"`
+	// Origin identifies the transfer being withdrawn
+	Origin string `json:"origin"`
+	// TxHash is the hash of the latest transaction sent, the signature for Solana
+	TxHash string `json:"tx_hash"`
+	Signer string `json:"signer"`
+	// LastValidHeight is the Solana block height after which the transaction can not be included anymore
+	LastValidHeight uint64    `json:"last_valid_height,omitempty"`
+	SubmittedAt     time.Time `json:"submitted_at"`
+	// Expected is the withdrawal the transaction has to make, it is checked against the chain events once
+	// the transaction is final. It is empty for the bridgers that do not verify the events.
+	Expected *Withdrawal `json:"expected,omitempty"`
+}
+
+// Withdrawal is the payout of the transfer in the target chain
+type Withdrawal struct {
+	Receiver string `json:"receiver"`
+	// Token is empty for the native token
+	Token   string `json:"token,omitempty"`
+	TokenID string `json:"token_id,omitempty"`
+	// Amount is empty for the non-fungible tokens
+	Amount string `json:"amount,omitempty"`
+}
+
+// Result is the final withdrawal transaction, it is kept for the status API, cost accounting and audit
+type Result struct {
+	Chain string `json:"chain"`
+	// TxHash is the transaction hash, the signature for Solana
+	TxHash string `json:"tx_hash"`
+	// Block is the block number, the slot for Solana
+	Block uint64 `json:"block"`
+	// GasUsed is zero for Solana that charges the transactions by the signatures and the compute budget
+	GasUsed uint64 `json:"gas_used"`
+	// EffectivePrice is the fee paid per unit of gas, it is nil when GasUsed is zero
+	EffectivePrice *big.Int `json:"effective_price,omitempty"`
+	// Fee is the total fee paid in the smallest units of the chain native token
+	Fee         *big.Int  `json:"fee"`
+	Signer      string    `json:"signer"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+}
+
+type Balance struct {
+	Signer string
+	Amount *big.Int
 }
 
 type FeeEstimate struct {
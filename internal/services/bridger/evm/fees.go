@@ -0,0 +1,123 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+// setFees prices the transaction according to the chain fee mode
+func setFees(ctx context.Context, client *ethclient.Client, chain *config.EVMChain, opts *bind.TransactOpts) error {
+	mode, err := chain.FeeMode(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if mode == config.EVMFeeModeLegacy {
+		gasPrice, err := suggestLegacyGasPrice(ctx, client, chain.Fees)
+		if err != nil {
+			return err
+		}
+
+		opts.GasPrice = gasPrice
+		return nil
+	}
+
+	tip, feeCap, err := suggestDynamicFees(ctx, client, chain.Fees)
+	if err != nil {
+		return err
+	}
+
+	opts.GasTipCap = tip
+	opts.GasFeeCap = feeCap
+	return nil
+}
+
+func suggestLegacyGasPrice(ctx context.Context, client *ethclient.Client, fees config.EVMFees) (*big.Int, error) {
+	suggested, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get suggested gas price")
+	}
+
+	if fees.MaxFee != nil && suggested.Cmp(fees.MaxFee) > 0 {
+		return nil, errors.From(bridge.ErrFeeTooHigh, logan.F{
+			"gas_price": suggested.String(),
+			"max_fee":   fees.MaxFee.String(),
+		})
+	}
+
+	return capFee(multiplyGasPrice(suggested, fees.GasPriceMultiplier), fees.MaxFee), nil
+}
+
+// suggestDynamicFees takes the tip from the priority fees paid in the latest blocks and builds
+// the fee cap on top of the next block base fee
+func suggestDynamicFees(ctx context.Context, client *ethclient.Client, fees config.EVMFees) (*big.Int, *big.Int, error) {
+	history, err := client.FeeHistory(ctx, fees.HistoryBlocks, nil, []float64{fees.TipPercentile})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get fee history")
+	}
+
+	// the base fees list includes the next block one in the end
+	if len(history.BaseFee) == 0 || history.BaseFee[len(history.BaseFee)-1].Sign() == 0 {
+		return nil, nil, errors.New("chain does not report the base fee, the legacy fee mode has to be used")
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	tip := median(history.Reward)
+	if tip == nil {
+		if tip, err = client.SuggestGasTipCap(ctx); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to get suggested gas tip")
+		}
+	}
+	if fees.MinTip != nil && tip.Cmp(fees.MinTip) < 0 {
+		tip = fees.MinTip
+	}
+
+	required := new(big.Int).Add(baseFee, tip)
+	if fees.MaxFee != nil && required.Cmp(fees.MaxFee) > 0 {
+		return nil, nil, errors.From(bridge.ErrFeeTooHigh, logan.F{
+			"base_fee": baseFee.String(),
+			"tip":      tip.String(),
+			"max_fee":  fees.MaxFee.String(),
+		})
+	}
+
+	feeCap := new(big.Int).Add(multiplyGasPrice(baseFee, fees.BaseFeeMultiplier), tip)
+
+	return tip, capFee(feeCap, fees.MaxFee), nil
+}
+
+// median returns the median of the first percentile rewards of the blocks, nil if there are none
+func median(rewards [][]*big.Int) *big.Int {
+	values := make([]*big.Int, 0, len(rewards))
+	for _, blockRewards := range rewards {
+		if len(blockRewards) > 0 && blockRewards[0] != nil {
+			values = append(values, blockRewards[0])
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+
+	return values[len(values)/2]
+}
+
+func capFee(fee, maxFee *big.Int) *big.Int {
+	if maxFee != nil && fee.Cmp(maxFee) > 0 {
+		return maxFee
+	}
+
+	return fee
+}
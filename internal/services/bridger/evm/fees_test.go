@@ -0,0 +1,84 @@
+package evm
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMedian(t *testing.T) {
+	rewards := func(values ...int64) [][]*big.Int {
+		result := make([][]*big.Int, len(values))
+		for i, value := range values {
+			result[i] = []*big.Int{big.NewInt(value)}
+		}
+
+		return result
+	}
+
+	cases := []struct {
+		name    string
+		rewards [][]*big.Int
+		want    *big.Int
+	}{
+		{name: "no blocks"},
+		{name: "blocks without rewards", rewards: [][]*big.Int{{}, {nil}}},
+		{name: "single block", rewards: rewards(5), want: big.NewInt(5)},
+		{name: "odd blocks", rewards: rewards(30, 10, 20), want: big.NewInt(20)},
+		{name: "even blocks", rewards: rewards(40, 10, 30, 20), want: big.NewInt(30)},
+		{name: "empty blocks skipped", rewards: append(rewards(7, 3), []*big.Int{}), want: big.NewInt(7)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := median(tc.rewards)
+			switch {
+			case tc.want == nil && got != nil:
+				t.Fatalf("expected nil, got %s", got)
+			case tc.want != nil && (got == nil || got.Cmp(tc.want) != 0):
+				t.Fatalf("expected %s, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCapFee(t *testing.T) {
+	cases := []struct {
+		name   string
+		fee    *big.Int
+		maxFee *big.Int
+		want   *big.Int
+	}{
+		{name: "no cap", fee: big.NewInt(100), want: big.NewInt(100)},
+		{name: "below cap", fee: big.NewInt(100), maxFee: big.NewInt(200), want: big.NewInt(100)},
+		{name: "above cap", fee: big.NewInt(300), maxFee: big.NewInt(200), want: big.NewInt(200)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := capFee(tc.fee, tc.maxFee); got.Cmp(tc.want) != 0 {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	cases := []struct {
+		name   string
+		fee    *big.Int
+		market *big.Int
+		want   *big.Int
+	}{
+		{name: "bumped", fee: big.NewInt(1000), want: big.NewInt(1125)},
+		{name: "market below bump", fee: big.NewInt(1000), market: big.NewInt(1100), want: big.NewInt(1125)},
+		{name: "market above bump", fee: big.NewInt(1000), market: big.NewInt(2000), want: big.NewInt(2000)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bumpFee(tc.fee, tc.market, 1.125); got.Cmp(tc.want) != 0 {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+)
+
+// draftGasLimit is set to the transaction built for the estimation only, so the bindings do not
+// estimate the gas on their own
+const draftGasLimit = 1
+
+// revertErrorCode is returned by geth-compatible nodes for the reverted calls
+const revertErrorCode = 3
+
+// estimateGasLimit estimates the gas the draft transaction needs and bounds it with the chain config
+func estimateGasLimit(
+	ctx context.Context,
+	client *ethclient.Client,
+	chain *config.EVMChain,
+	from common.Address,
+	draft *types.Transaction,
+	tokenType string,
+) (uint64, error) {
+	estimated, err := client.EstimateGas(ctx, callMsg(from, draft))
+	if err != nil {
+		if isRevert(err) {
+			return 0, revertError(err)
+		}
+
+		return 0, errors.Wrap(err, "failed to estimate gas")
+	}
+
+	return chain.Gas.Limit(tokenType, estimated), nil
+}
+
+// simulate executes the signed transaction with eth_call, so it is not sent if it would revert with
+// the final gas limit and fees. The block of nil simulates the transaction on top of the latest one.
+func simulate(ctx context.Context, client *ethclient.Client, from common.Address, tx *types.Transaction, block *big.Int) error {
+	msg := callMsg(from, tx)
+	msg.Gas = tx.Gas()
+
+	if _, err := client.CallContract(ctx, msg, block); err != nil {
+		if isRevert(err) {
+			return revertError(err)
+		}
+
+		return errors.Wrap(err, "failed to simulate the transaction")
+	}
+
+	return nil
+}
+
+func callMsg(from common.Address, tx *types.Transaction) ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		msg.GasFeeCap, msg.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	} else {
+		msg.GasPrice = tx.GasPrice()
+	}
+
+	return msg
+}
+
+// isRevert tells the reverted calls from the failures of the node itself
+func isRevert(err error) bool {
+	if rpcErr, ok := errors.Cause(err).(rpc.Error); ok && rpcErr.ErrorCode() == revertErrorCode {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "execution reverted")
+}
@@ -3,31 +3,42 @@ package evm
 import (
 	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	bridgebind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/bridge"
 	facadebind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/interfaces/facade"
 	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	tokenmanager "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"github.com/rarimo/relayer-svc/internal/config"
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/failover"
+	"github.com/rarimo/relayer-svc/internal/nonce"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/rarimo/relayer-svc/internal/signer"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"github.com/rarimo/relayer-svc/internal/tracing"
 	"github.com/rarimo/relayer-svc/internal/utils"
-	"github.com/rarimo/relayer-svc/pkg/secret"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
+	"time"
 )
 
-const GAS_PRICE_MULTIPLIER = 1.3
-
 type evmBridger struct {
 	log          *logan.Entry
 	tokenmanager tokenmanager.QueryClient
 	evm          *config.EVM
-	vault        secret.Vault
+	networks     *config.Networks
+	nonces       nonce.Allocator
+	redis        redis.Rediser
+	signer       signer.Signer
+	signers      *signerpool.Pool
 }
 
 func NewEVMBridger(cfg config.Config) bridge.Bridger {
@@ -35,17 +46,23 @@ func NewEVMBridger(cfg config.Config) bridge.Bridger {
 		log:          cfg.Log().WithField("service", "evm_bridge"),
 		tokenmanager: tokenmanager.NewQueryClient(cfg.Cosmos()),
 		evm:          cfg.EVM(),
-		vault:        cfg.Vault(),
+		networks:     cfg.Networks(),
+		nonces:       cfg.Nonces(),
+		redis:        cfg.Redis(),
+		signer:       cfg.Signer(),
+		signers:      cfg.SignerPool(),
 	}
 }
 
 func (b *evmBridger) makeWithdrawTx(
 	ctx context.Context,
 	chain *config.EVMChain,
+	client *ethclient.Client,
+	signer common.Address,
 	transfer core.TransferDetails,
 	simulation bool,
-) (*types.Transaction, error) {
-	bridgeFacade, err := facadebind.NewIBridgeFacade(chain.BridgeFacadeAddress, chain.RPC)
+) (_ *types.Transaction, err error) {
+	bridgeFacade, err := facadebind.NewIBridgeFacade(chain.BridgeFacadeAddress, client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to make an instance of the ethereum bridge facade")
 	}
@@ -72,134 +89,434 @@ func (b *evmBridger) makeWithdrawTx(
 		return nil, errors.Wrap(err, "failed to parse bundle data")
 	}
 
-	opts, err := bind.NewKeyedTransactorWithChainID(b.vault.Secret().EVM().PrivateKey(chain.Name), chain.ChainID)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create a bridge transactor")
-	}
-
-	opts.Context = ctx
-	opts.NoSend = simulation
-	nonce, err := chain.RPC.PendingNonceAt(ctx, b.vault.Secret().EVM().PublicKey(chain.Name))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to fetch a nonce")
-	}
-	opts.Nonce = big.NewInt(int64(nonce))
-	gasPrice, err := chain.RPC.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get suggested gas price")
-	}
-	opts.GasPrice = multiplyGasPrice(gasPrice, GAS_PRICE_MULTIPLIER)
-	opts.GasLimit = uint64(1000000) // TODO: estimate or make it configurable
-
-	switch transfer.CollectionData.TokenType {
-	case tokenmanager.Type_NATIVE:
-		return bridgeFacade.WithdrawNative(
-			opts,
-			facadebind.INativeHandlerWithdrawNativeParameters{
-				Amount:     amount,
-				Bundle:     bundle,
-				OriginHash: origin,
-				Receiver:   receiver,
-				Proof:      proof,
-			},
-		)
-	case tokenmanager.Type_ERC20:
-		return bridgeFacade.WithdrawERC20(
-			opts,
-			facadebind.IERC20HandlerWithdrawERC20Parameters{
-				Token:      common.HexToAddress(transfer.Transfer.To.Address),
-				Amount:     amount,
-				Bundle:     bundle,
-				OriginHash: origin,
-				Receiver:   receiver,
-				Proof:      proof,
-				IsWrapped:  transfer.CollectionData.Wrapped,
-			},
-		)
-	case tokenmanager.Type_ERC721:
-		tokenID, err := parseTokenID(transfer.Transfer.To.TokenID)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse the tokenID")
-		}
+	chainID, err := chain.GetChainID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the chain id")
+	}
 
-		return bridgeFacade.WithdrawERC721(
-			opts,
-			facadebind.IERC721HandlerWithdrawERC721Parameters{
-				Token:      common.HexToAddress(transfer.Transfer.To.Address),
-				TokenId:    tokenID,
-				TokenURI:   transfer.Item.Meta.Uri,
-				Bundle:     bundle,
-				OriginHash: origin,
-				Receiver:   receiver,
-				Proof:      proof,
-				IsWrapped:  transfer.CollectionData.Wrapped,
-			})
-	case tokenmanager.Type_ERC1155:
-		tokenID, err := parseTokenID(transfer.Transfer.To.TokenID)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse the tokenID")
+	txSigner := types.LatestSignerForChainID(chainID)
+	opts := &bind.TransactOpts{
+		From: signer,
+		Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return b.signTx(ctx, chain.Name, txSigner, from, tx)
+		},
+		Context: ctx,
+	}
+
+	account := nonceAccount(chain.Name, opts.From)
+	txNonce, err := b.nextNonce(ctx, client, account, opts.From)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to allocate a nonce")
+	}
+	// the simulated transaction never reaches the node, so its nonce is always handed out again
+	defer func() { b.settleNonce(ctx, account, txNonce, !simulation, err) }()
+	opts.Nonce = new(big.Int).SetUint64(txNonce)
+	if err = setFees(ctx, client, chain, opts); err != nil {
+		return nil, errors.Wrap(err, "failed to price the transaction")
+	}
+
+	withdraw := func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		switch transfer.CollectionData.TokenType {
+		case tokenmanager.Type_NATIVE:
+			return bridgeFacade.WithdrawNative(
+				opts,
+				facadebind.INativeHandlerWithdrawNativeParameters{
+					Amount:     amount,
+					Bundle:     bundle,
+					OriginHash: origin,
+					Receiver:   receiver,
+					Proof:      proof,
+				},
+			)
+		case tokenmanager.Type_ERC20:
+			return bridgeFacade.WithdrawERC20(
+				opts,
+				facadebind.IERC20HandlerWithdrawERC20Parameters{
+					Token:      common.HexToAddress(transfer.Transfer.To.Address),
+					Amount:     amount,
+					Bundle:     bundle,
+					OriginHash: origin,
+					Receiver:   receiver,
+					Proof:      proof,
+					IsWrapped:  transfer.CollectionData.Wrapped,
+				},
+			)
+		case tokenmanager.Type_ERC721:
+			tokenID, err := parseTokenID(transfer.Transfer.To.TokenID)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse the tokenID")
+			}
+
+			return bridgeFacade.WithdrawERC721(
+				opts,
+				facadebind.IERC721HandlerWithdrawERC721Parameters{
+					Token:      common.HexToAddress(transfer.Transfer.To.Address),
+					TokenId:    tokenID,
+					TokenURI:   transfer.Item.Meta.Uri,
+					Bundle:     bundle,
+					OriginHash: origin,
+					Receiver:   receiver,
+					Proof:      proof,
+					IsWrapped:  transfer.CollectionData.Wrapped,
+				})
+		case tokenmanager.Type_ERC1155:
+			tokenID, err := parseTokenID(transfer.Transfer.To.TokenID)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse the tokenID")
+			}
+
+			return bridgeFacade.WithdrawERC1155(
+				opts,
+				facadebind.IERC1155HandlerWithdrawERC1155Parameters{
+					Token:      common.HexToAddress(transfer.Transfer.To.Address),
+					TokenId:    tokenID,
+					TokenURI:   transfer.Item.Meta.Uri,
+					Amount:     amount,
+					Bundle:     bundle,
+					OriginHash: origin,
+					Receiver:   receiver,
+					Proof:      proof,
+					IsWrapped:  transfer.CollectionData.Wrapped,
+				})
+		default:
+			return nil, errors.Errorf("token type %d is not supported", transfer.CollectionData.TokenType)
 		}
+	}
+
+	// the transaction is built without sending at first to estimate the gas for its calldata, the draft
+	// is left unsigned as only its calldata is used
+	signTx := opts.Signer
+	opts.Signer = func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) { return tx, nil }
+	opts.NoSend = true
+	opts.GasLimit = draftGasLimit
+	draft, err := withdraw(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.Signer = signTx
+
+	opts.GasLimit, err = estimateGasLimit(ctx, client, chain, opts.From, draft, transfer.CollectionData.TokenType.String())
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := withdraw(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = simulate(ctx, client, opts.From, tx, nil); err != nil {
+		return nil, err
+	}
+	if simulation {
+		return tx, nil
+	}
 
-		return bridgeFacade.WithdrawERC1155(
-			opts,
-			facadebind.IERC1155HandlerWithdrawERC1155Parameters{
-				Token:      common.HexToAddress(transfer.Transfer.To.Address),
-				TokenId:    tokenID,
-				TokenURI:   transfer.Item.Meta.Uri,
-				Amount:     amount,
-				Bundle:     bundle,
-				OriginHash: origin,
-				Receiver:   receiver,
-				Proof:      proof,
-				IsWrapped:  transfer.CollectionData.Wrapped,
-			})
-	default:
-		return nil, errors.Errorf("token type %d is not supported", transfer.CollectionData.TokenType)
+	if err = client.SendTransaction(ctx, tx); err != nil {
+		return nil, errors.Wrap(err, "failed to send the transaction")
 	}
+
+	return tx, nil
 }
 
-func (b *evmBridger) Withdraw(
+func (b *evmBridger) Submit(
 	ctx context.Context,
 	transfer core.TransferDetails,
-) error {
+) (_ *bridge.Submission, err error) {
+	ctx, span := tracing.Start(ctx, "evm.Submit", attribute.String("op_id", transfer.Origin))
+	defer func() { tracing.End(span, err) }()
+
 	log := b.log.WithField("op_id", transfer.Origin)
 
-	targetChain := b.mustGetChain(transfer.Transfer.To.Chain)
+	targetChain, err := b.getChain(transfer.Transfer.To.Chain)
+	if err != nil {
+		return nil, err
+	}
 
-	withdrawn, err := b.isAlreadyWithdrawn(ctx, targetChain, transfer)
+	withdrawn, err := b.isAlreadyWithdrawn(ctx, targetChain, transfer.Origin)
 	if err != nil {
-		return errors.Wrap(err, "failed to check if the transfer was already withdrawn")
+		return nil, errors.Wrap(err, "failed to check if the transfer was already withdrawn")
 	}
 	if withdrawn {
-		return bridge.ErrAlreadyWithdrawn
+		return nil, bridge.ErrAlreadyWithdrawn
+	}
+
+	client, done, err := targetChain.RPC.Acquire()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the rpc client")
+	}
+	defer func() { done(err) }()
+
+	expected, err := expectedWithdrawal(transfer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe the expected withdrawal")
+	}
+
+	tx, err := b.pendingTx(ctx, targetChain.Name, client, transfer.Origin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check the previously sent transactions")
+	}
+
+	var signer common.Address
+	if tx != nil {
+		if signer, err = types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err != nil {
+			return nil, errors.Wrap(err, "failed to recover the pending transaction signer")
+		}
+
+		log.WithField("tx_id", tx.Hash()).Info("resuming the pending transaction")
+	} else {
+		if signer, err = b.selectSigner(ctx, targetChain); err != nil {
+			return nil, err
+		}
+
+		tx, err = b.makeWithdrawTx(ctx, targetChain, client, signer, transfer, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to call the withdraw method")
+		}
+
+		log.WithFields(logan.F{"tx_id": tx.Hash(), "signer": signer}).Info("submitted transaction")
+		b.recordTx(ctx, transfer.Origin, targetChain.Name, redis.TaskEventSubmitted, tx)
+	}
+
+	return &bridge.Submission{
+		Chain:       targetChain.Name,
+		Origin:      transfer.Origin,
+		TxHash:      tx.Hash().Hex(),
+		Signer:      signer.Hex(),
+		SubmittedAt: time.Now().UTC(),
+		Expected:    expected,
+	}, nil
+}
+
+func (b *evmBridger) Confirm(ctx context.Context, submission *bridge.Submission) (_ *bridge.Result, err error) {
+	log := b.log.WithField("op_id", submission.Origin)
+
+	targetChain, err := b.getChain(submission.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	client, done, err := targetChain.RPC.Acquire()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the rpc client")
 	}
+	defer func() { done(err) }()
 
-	tx, err := b.makeWithdrawTx(ctx, targetChain, transfer, false)
+	sent, mined, err := b.sentTxs(ctx, submission)
 	if err != nil {
-		return errors.Wrap(err, "failed to call the withdraw method")
+		return nil, err
 	}
 
-	log.WithField("tx_id", tx.Hash()).Info("submitted transaction")
+	// the receipt is requested on every check, so the transaction reorged away before it is deep enough
+	// is noticed as the missing receipt
+	receipt, err := findReceipt(ctx, client, sent)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		if len(mined) != 0 {
+			log.WithField("tx_ids", mined).Warn("mined transaction is no longer included, it was reorged away")
+		}
+
+		err = b.replaceIfStuck(ctx, targetChain, client, submission, sent)
+		if errors.Cause(err) == bridge.ErrTxDropped {
+			return nil, b.droppedTxError(ctx, targetChain, submission, err)
+		}
+
+		return nil, err
+	}
 
-	receipt, err := bind.WaitMined(ctx, targetChain.RPC, tx)
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
 	if err != nil {
-		return errors.Wrap(err, "failed to wait for the transaction to be mined")
+		return nil, errors.Wrap(err, "failed to get the mined transaction", logan.F{"tx_id": receipt.TxHash})
+	}
+
+	if receipt.Status == types.ReceiptStatusFailed {
+		log.WithField("receipt", utils.Prettify(receipt)).Errorf("%s transaction failed", submission.Chain)
+		return nil, b.failedTxError(ctx, client, tx, receipt)
 	}
-	if receipt.Status == 0 {
-		log.WithField("receipt", utils.Prettify(receipt)).Errorf("%s transaction failed", transfer.Transfer.To.Chain)
-		return errors.New("transaction failed")
+
+	if !mined[receipt.TxHash.Hex()] {
+		b.recordTx(ctx, submission.Origin, submission.Chain, redis.TaskEventMined, tx)
+	}
+
+	depth, err := confirmationDepth(ctx, client, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if depth < targetChain.Confirmations {
+		log.WithFields(logan.F{
+			"tx_id":         receipt.TxHash,
+			"confirmations": depth,
+			"required":      targetChain.Confirmations,
+		}).Debug("waiting for the transaction confirmations")
+		return nil, bridge.ErrPending
+	}
+
+	if err = b.verifyWithdrawal(ctx, targetChain, client, submission, receipt); err != nil {
+		return nil, err
+	}
+
+	price, err := effectiveGasPrice(ctx, client, tx, receipt)
+	if err != nil {
+		return nil, err
 	}
 
+	submission.TxHash = receipt.TxHash.Hex()
 	log.
 		WithFields(logan.F{
-			"tx_id":        tx.Hash(),
-			"tx_index":     receipt.TransactionIndex,
-			"block_number": receipt.BlockNumber,
-			"gas_used":     receipt.GasUsed,
+			"tx_id":           receipt.TxHash,
+			"tx_index":        receipt.TransactionIndex,
+			"block_number":    receipt.BlockNumber,
+			"gas_used":        receipt.GasUsed,
+			"effective_price": price,
 		}).
 		Info("evm transaction confirmed")
 
-	return nil
+	return &bridge.Result{
+		Chain:          submission.Chain,
+		TxHash:         receipt.TxHash.Hex(),
+		Block:          receipt.BlockNumber.Uint64(),
+		GasUsed:        receipt.GasUsed,
+		EffectivePrice: price,
+		Fee:            new(big.Int).Mul(price, new(big.Int).SetUint64(receipt.GasUsed)),
+		Signer:         submission.Signer,
+		ConfirmedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// confirmationDepth returns the number of blocks including the receipt block that are built on top of the chain
+func confirmationDepth(ctx context.Context, client *ethclient.Client, receipt *types.Receipt) (uint64, error) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get the latest block number")
+	}
+
+	block := receipt.BlockNumber.Uint64()
+	if head < block {
+		// the endpoint serving the request may lag behind the one the receipt was requested from
+		return 0, nil
+	}
+
+	return head - block + 1, nil
+}
+
+// droppedTxError checks the bridge contract before the dropped transaction is reported, as the transfer is sent
+// again after it and the withdrawal may be included already by the transaction that is not known to the node
+func (b *evmBridger) droppedTxError(ctx context.Context, chain *config.EVMChain, submission *bridge.Submission, dropped error) error {
+	withdrawn, err := b.isAlreadyWithdrawn(ctx, chain, submission.Origin)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the dropped transfer was withdrawn")
+	}
+	if withdrawn {
+		return bridge.ErrAlreadyWithdrawn
+	}
+
+	return dropped
+}
+
+// findReceipt returns the receipt of any of the transactions sent with the same nonce, nil if none is mined
+func findReceipt(ctx context.Context, client *ethclient.Client, sent []redis.TaskEvent) (*types.Receipt, error) {
+	for _, event := range sent {
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(event.TxHash))
+		if err == ethereum.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the transaction receipt", logan.F{"tx_id": event.TxHash})
+		}
+
+		return receipt, nil
+	}
+
+	return nil, nil
+}
+
+// effectiveGasPrice returns the price the mined transaction paid per gas, the receipts of go-ethereum
+// do not carry it, so the dynamic fee transaction price is derived from the block base fee
+func effectiveGasPrice(ctx context.Context, client *ethclient.Client, tx *types.Transaction, receipt *types.Receipt) (*big.Int, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return tx.GasPrice(), nil
+	}
+
+	header, err := client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the block header", logan.F{"block_number": receipt.BlockNumber})
+	}
+
+	tip, err := tx.EffectiveGasTip(header.BaseFee)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the effective gas tip")
+	}
+
+	return new(big.Int).Add(header.BaseFee, tip), nil
+}
+
+func (b *evmBridger) SignerBalances(ctx context.Context, chain string) ([]bridge.Balance, error) {
+	targetChain, err := b.getChain(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := b.signer.Keys(ctx, targetChain.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the signer keys")
+	}
+
+	balances := make([]bridge.Balance, len(keys))
+	for i, key := range keys {
+		var balance *big.Int
+		err = targetChain.RPC.Do(func(client *ethclient.Client) (err error) {
+			balance, err = client.BalanceAt(ctx, common.HexToAddress(key.Address), nil)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the signer balance", logan.F{"signer": key.Address})
+		}
+
+		balances[i] = bridge.Balance{Signer: key.Address, Amount: balance}
+	}
+
+	return balances, nil
+}
+
+// selectSigner picks the key of the chain signers pool for the new withdrawal
+func (b *evmBridger) selectSigner(ctx context.Context, chain *config.EVMChain) (common.Address, error) {
+	keys, err := b.signer.Keys(ctx, chain.Name)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "failed to get the signer keys")
+	}
+	if len(keys) == 0 {
+		return common.Address{}, errors.From(errors.New("no signer keys configured"), logan.F{"chain": chain.Name})
+	}
+
+	selected, err := b.signers.Select(ctx, chain.Name, signer.Addresses(keys))
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "failed to select the signer")
+	}
+
+	return common.HexToAddress(selected), nil
+}
+
+// signTx signs the transaction of the chain with the key of the sender through the signer backend
+func (b *evmBridger) signTx(
+	ctx context.Context,
+	chain string,
+	txSigner types.Signer,
+	from common.Address,
+	tx *types.Transaction,
+) (*types.Transaction, error) {
+	signature, err := b.signer.SignDigest(ctx, chain, from.Hex(), txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign the transaction", logan.F{"signer": from})
+	}
+
+	signed, err := tx.WithSignature(txSigner, signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to attach the signature")
+	}
+
+	return signed, nil
 }
 
 func getBundleData(transfer rarimocore.Transfer) (facadebind.IBundlerBundle, error) {
@@ -232,29 +549,49 @@ func parseTokenID(rawTokenID string) (*big.Int, error) {
 	return big.NewInt(0).SetBytes(rawBytes), nil
 }
 
-func (b *evmBridger) mustGetChain(chainName string) *config.EVMChain {
+func (b *evmBridger) getChain(chainName string) (*config.EVMChain, error) {
 	chain, ok := b.evm.GetChainByName(chainName)
 	if !ok {
-		panic(errors.Errorf("unknown EVM chain: %s", chainName))
+		return nil, errors.From(bridge.ErrUnknownChain, logan.F{"chain": chainName})
 	}
 
-	return chain
+	return chain, nil
+}
+
+// bridgeAddress prefers the bridge contract registered in the core params to the configured one
+func (b *evmBridger) bridgeAddress(chain *config.EVMChain) (common.Address, error) {
+	if network, ok := b.networks.Get(chain.Name); ok && common.IsHexAddress(network.Contract) {
+		return common.HexToAddress(network.Contract), nil
+	}
+
+	if chain.BridgeAddress == (common.Address{}) {
+		return common.Address{}, errors.From(errors.New("bridge address is neither discovered nor configured"), logan.F{
+			"chain": chain.Name,
+		})
+	}
+
+	return chain.BridgeAddress, nil
 }
 
 func (b *evmBridger) isAlreadyWithdrawn(
 	ctx context.Context,
 	chain *config.EVMChain,
-	transfer core.TransferDetails,
+	transferOrigin string,
 ) (bool, error) {
-	bridger, err := bridgebind.NewBridge(chain.BridgeAddress, chain.RPC)
+	origin := utils.ToByte32(hexutil.MustDecode(transferOrigin))
+	bridgeAddress, err := b.bridgeAddress(chain)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to make an instance of ethereum bridger")
+		return false, err
 	}
 
-	withdrawn, err := bridger.BridgeCaller.UsedHashes(
-		&bind.CallOpts{Pending: false, Context: ctx},
-		utils.ToByte32(hexutil.MustDecode(transfer.Origin)),
-	)
+	withdrawn, err := failover.Quorum(ctx, chain.RPC, chain.Quorum, func(ctx context.Context, client *ethclient.Client) (bool, error) {
+		bridger, err := bridgebind.NewBridgeCaller(bridgeAddress, client)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to make an instance of ethereum bridger")
+		}
+
+		return bridger.UsedHashes(&bind.CallOpts{Pending: false, Context: ctx}, origin)
+	})
 	if err != nil {
 		return false, errors.Wrap(err, "failed to check if the transfer was already withdrawn")
 	}
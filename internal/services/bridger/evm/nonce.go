@@ -0,0 +1,54 @@
+package evm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gitlab.com/distributed_lab/logan/v3"
+
+	"github.com/rarimo/relayer-svc/internal/nonce"
+)
+
+// nonceErrors are the node rejections meaning the allocated nonce is out of sync with the chain
+var nonceErrors = []string{
+	"nonce too low",
+	"nonce too high",
+	"replacement transaction underpriced",
+	"already known",
+	"known transaction",
+}
+
+func nonceAccount(chain string, signer common.Address) string {
+	return nonce.Account(chain, signer.Hex())
+}
+
+func (b *evmBridger) nextNonce(ctx context.Context, client *ethclient.Client, account string, signer common.Address) (uint64, error) {
+	return b.nonces.Next(ctx, account, func(ctx context.Context) (uint64, error) {
+		return client.PendingNonceAt(ctx, signer)
+	})
+}
+
+// settleNonce returns the nonce of the transaction that did not reach the node to the allocator,
+// resyncing it with the node when the nonce was rejected
+func (b *evmBridger) settleNonce(ctx context.Context, account string, txNonce uint64, sent bool, err error) {
+	settleErr := nonce.Settle(ctx, b.nonces, account, txNonce, sent && err == nil, err != nil && isNonceError(err))
+	if settleErr != nil {
+		b.log.WithError(settleErr).WithFields(logan.F{
+			"account": account,
+			"nonce":   txNonce,
+		}).Error("failed to settle the nonce")
+	}
+}
+
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, nonceErr := range nonceErrors {
+		if strings.Contains(msg, nonceErr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,248 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+// dropGracePeriod is the time the node may not know the sent transaction before it is considered dropped,
+// as the transaction may not be propagated to the endpoint the confirmation is checked with yet
+const dropGracePeriod = time.Minute
+
+// errFeesAtCap is returned when the fees can not be bumped without exceeding the chain fee cap
+var errFeesAtCap = errors.New("fees are at the cap")
+
+// replaceIfStuck replaces the latest transaction with the bumped fees once the chain replacement timeout
+// passes since it was sent, returning bridge.ErrPending while the transaction may still be included
+func (b *evmBridger) replaceIfStuck(
+	ctx context.Context,
+	chain *config.EVMChain,
+	client *ethclient.Client,
+	submission *bridge.Submission,
+	sent []redis.TaskEvent,
+) error {
+	latest := sent[len(sent)-1]
+	fields := logan.F{"tx_id": latest.TxHash}
+
+	tx, _, err := client.TransactionByHash(ctx, common.HexToHash(latest.TxHash))
+	switch {
+	case err == ethereum.NotFound && time.Since(latest.At) < dropGracePeriod:
+		return bridge.ErrPending
+	case err == ethereum.NotFound:
+		return errors.From(bridge.ErrTxDropped, fields)
+	case err != nil:
+		return errors.Wrap(err, "failed to get the pending transaction", fields)
+	}
+
+	timeout := chain.Replacement.Timeout
+	if timeout == 0 || time.Since(latest.At) < timeout {
+		return bridge.ErrPending
+	}
+
+	if len(sent)-1 >= chain.Replacement.MaxReplacements {
+		return errors.From(bridge.ErrTxStuck, fields.Merge(logan.F{"nonce": tx.Nonce()}))
+	}
+
+	replacement, err := b.replaceTx(ctx, chain, client, tx)
+	switch {
+	case err == errFeesAtCap:
+		return errors.From(bridge.ErrTxStuck, fields.Merge(logan.F{"nonce": tx.Nonce()}))
+	case err != nil:
+		// the replacement is rejected when one of the sent transactions is mined meanwhile,
+		// so the receipts are checked again before the next attempt
+		b.log.WithError(err).WithFields(fields).Warn("failed to replace the stuck transaction")
+		return bridge.ErrPending
+	}
+
+	b.log.WithFields(logan.F{
+		"op_id":          submission.Origin,
+		"tx_id":          replacement.Hash(),
+		"replaced_tx_id": latest.TxHash,
+		"nonce":          replacement.Nonce(),
+	}).Info("replaced the stuck transaction")
+	b.recordTx(ctx, submission.Origin, chain.Name, redis.TaskEventReplaced, replacement)
+	submission.TxHash = replacement.Hash().Hex()
+
+	return bridge.ErrPending
+}
+
+// sentTxs returns the transactions sent for the submission, that is the latest submitted one and its replacements,
+// along with the hashes of those that were seen mined
+func (b *evmBridger) sentTxs(ctx context.Context, submission *bridge.Submission) ([]redis.TaskEvent, map[string]bool, error) {
+	history, err := b.redis.TaskHistory(ctx, submission.Origin)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get the task history")
+	}
+
+	sent := make([]redis.TaskEvent, 0, len(history))
+	mined := make(map[string]bool)
+	for _, event := range history {
+		if event.Chain != submission.Chain || event.TxHash == "" {
+			continue
+		}
+
+		switch event.Event {
+		case redis.TaskEventSubmitted:
+			sent = append(sent[:0], event)
+			mined = make(map[string]bool)
+		case redis.TaskEventReplaced:
+			sent = append(sent, event)
+		case redis.TaskEventMined:
+			mined[event.TxHash] = true
+		}
+	}
+
+	if len(sent) == 0 {
+		// the history may fail to be recorded, the submission itself is enough to confirm the transaction
+		sent = append(sent, redis.TaskEvent{
+			Event:  redis.TaskEventSubmitted,
+			Chain:  submission.Chain,
+			TxHash: submission.TxHash,
+			At:     submission.SubmittedAt,
+		})
+	}
+
+	return sent, mined, nil
+}
+
+// replaceTx re-signs the transaction with the same nonce and the fees bumped by the chain fee bump,
+// taking the current market fees if they grew even more
+func (b *evmBridger) replaceTx(
+	ctx context.Context,
+	chain *config.EVMChain,
+	client *ethclient.Client,
+	tx *types.Transaction,
+) (*types.Transaction, error) {
+	chainID, err := chain.GetChainID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the chain id")
+	}
+
+	// the current market fees are only a lower bound for the bump, so the replacement is sent
+	// even if they can not be fetched or exceed the cap
+	market := &bind.TransactOpts{}
+	if err = setFees(ctx, client, chain, market); err != nil {
+		market = &bind.TransactOpts{}
+	}
+
+	var replacement types.TxData
+	if tx.Type() == types.DynamicFeeTxType {
+		feeCap := capFee(bumpFee(tx.GasFeeCap(), market.GasFeeCap, chain.Replacement.FeeBump), chain.Fees.MaxFee)
+		if feeCap.Cmp(tx.GasFeeCap()) <= 0 {
+			return nil, errFeesAtCap
+		}
+
+		tip := bumpFee(tx.GasTipCap(), market.GasTipCap, chain.Replacement.FeeBump)
+		if tip.Cmp(feeCap) > 0 {
+			tip = feeCap
+		}
+
+		replacement = &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     tx.Nonce(),
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		}
+	} else {
+		gasPrice := capFee(bumpFee(tx.GasPrice(), market.GasPrice, chain.Replacement.FeeBump), chain.Fees.MaxFee)
+		if gasPrice.Cmp(tx.GasPrice()) <= 0 {
+			return nil, errFeesAtCap
+		}
+
+		replacement = &types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}
+	}
+
+	// the replacement has to come from the same signer to take the nonce of the stuck transaction
+	txSigner := types.LatestSignerForChainID(chainID)
+	from, err := types.Sender(txSigner, tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recover the stuck transaction signer")
+	}
+
+	signed, err := b.signTx(ctx, chain.Name, txSigner, from, types.NewTx(replacement))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign the replacement transaction")
+	}
+
+	if err = client.SendTransaction(ctx, signed); err != nil {
+		return nil, errors.Wrap(err, "failed to send the replacement transaction")
+	}
+
+	return signed, nil
+}
+
+// pendingTx returns the latest transaction recorded for the transfer that is still in the mempool,
+// so the retried withdrawal keeps waiting for it instead of sending a duplicate
+func (b *evmBridger) pendingTx(ctx context.Context, chain string, client *ethclient.Client, origin string) (*types.Transaction, error) {
+	history, err := b.redis.TaskHistory(ctx, origin)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the task history")
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		event := history[i]
+		if event.Chain != chain || event.TxHash == "" {
+			continue
+		}
+
+		tx, isPending, err := client.TransactionByHash(ctx, common.HexToHash(event.TxHash))
+		switch {
+		case err == ethereum.NotFound:
+			continue
+		case err != nil:
+			return nil, errors.Wrap(err, "failed to get the transaction", logan.F{"tx_id": event.TxHash})
+		case isPending:
+			return tx, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *evmBridger) recordTx(ctx context.Context, origin, chain, event string, tx *types.Transaction) {
+	err := b.redis.RecordTaskEvent(ctx, origin, redis.TaskEvent{
+		Event:  event,
+		Chain:  chain,
+		TxHash: tx.Hash().Hex(),
+	})
+	if err != nil {
+		b.log.WithError(err).WithFields(logan.F{
+			"op_id": origin,
+			"tx_id": tx.Hash(),
+		}).Error("failed to record the transaction in the task history")
+	}
+}
+
+// bumpFee multiplies the fee by the bump, taking the market fee instead if it is higher
+func bumpFee(fee, market *big.Int, bump float64) *big.Int {
+	bumped := multiplyGasPrice(fee, bump)
+	if market != nil && market.Cmp(bumped) > 0 {
+		return market
+	}
+
+	return bumped
+}
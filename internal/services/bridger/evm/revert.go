@@ -0,0 +1,135 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	bridgebind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/bridge"
+	facadebind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/interfaces/facade"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+// panicSelector is the selector of the Panic(uint256) revert data emitted by the failed assertions
+var panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+// revertABIs are the contracts the withdrawal call goes through, their custom errors are decoded
+var revertABIs = mustParseABIs(facadebind.IBridgeFacadeMetaData, bridgebind.BridgeMetaData)
+
+// knownReverts map the revert reasons of the bridge contracts to the typed errors
+var knownReverts = []struct {
+	substr string
+	err    error
+}{
+	{"Hashes: the hash nonce is used", bridge.ErrAlreadyWithdrawn},
+	{"Signers: invalid signature", bridge.ErrInvalidSignature},
+	{"ECDSA: invalid signature", bridge.ErrInvalidSignature},
+	{": zero receiver", bridge.ErrInvalidWithdrawParams},
+	{": receiver is zero", bridge.ErrInvalidWithdrawParams},
+	{": zero token", bridge.ErrInvalidWithdrawParams},
+	{"NativeHandler: failed to send eth", bridge.ErrInsufficientLiquidity},
+	{"transfer amount exceeds balance", bridge.ErrInsufficientLiquidity},
+	{"insufficient balance for transfer", bridge.ErrInsufficientLiquidity},
+}
+
+func mustParseABIs(metadata ...interface{ GetAbi() (*abi.ABI, error) }) []*abi.ABI {
+	parsed := make([]*abi.ABI, len(metadata))
+	for i, meta := range metadata {
+		contractABI, err := meta.GetAbi()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to parse the contract ABI"))
+		}
+
+		parsed[i] = contractABI
+	}
+
+	return parsed
+}
+
+// revertError maps the reverted call error to the typed one by its decoded reason
+func revertError(err error) error {
+	reason := revertReason(err)
+	for _, known := range knownReverts {
+		if strings.Contains(reason, known.substr) {
+			return errors.From(known.err, logan.F{"reason": reason})
+		}
+	}
+
+	return errors.From(bridge.ErrWithdrawReverted, logan.F{"reason": reason})
+}
+
+// revertReason decodes the revert data of the call error, falling back to the error message
+// for the nodes that do not return the data
+func revertReason(err error) string {
+	dataErr, ok := errors.Cause(err).(rpc.DataError)
+	if !ok {
+		return err.Error()
+	}
+
+	rawData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error()
+	}
+
+	data, decodeErr := hexutil.Decode(rawData)
+	if decodeErr != nil || len(data) < 4 {
+		return err.Error()
+	}
+
+	if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+		return reason
+	}
+
+	if bytes.Equal(data[:4], panicSelector) && len(data) >= 36 {
+		return fmt.Sprintf("panic: 0x%x", new(big.Int).SetBytes(data[4:36]))
+	}
+
+	for _, contractABI := range revertABIs {
+		for _, customErr := range contractABI.Errors {
+			if !bytes.Equal(customErr.ID[:4], data[:4]) {
+				continue
+			}
+
+			args, unpackErr := customErr.Unpack(data)
+			if unpackErr != nil {
+				return customErr.Name
+			}
+
+			return fmt.Sprintf("%s%v", customErr.Name, args)
+		}
+	}
+
+	return fmt.Sprintf("unknown revert data %s", rawData)
+}
+
+// failedTxError replays the reverted transaction in the block it was mined in to get the revert reason,
+// the state may differ from the one the transaction was executed against, so the reason is the best effort
+func (b *evmBridger) failedTxError(ctx context.Context, client *ethclient.Client, tx *types.Transaction, receipt *types.Receipt) error {
+	fields := logan.F{"tx_id": tx.Hash(), "block_number": receipt.BlockNumber}
+	if receipt.GasUsed >= tx.Gas() {
+		// running out of gas is not a property of the transfer, the retry estimates the gas again
+		return errors.From(bridge.ErrOutOfGas, fields)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return errors.Wrap(err, "failed to recover the transaction sender", fields)
+	}
+
+	if err = simulate(ctx, client, from, tx, receipt.BlockNumber); err != nil {
+		return errors.Wrap(err, "transaction failed", fields)
+	}
+
+	return errors.From(bridge.ErrTxFailed, fields)
+}
@@ -0,0 +1,187 @@
+package evm
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+// dataError is the execution error with the revert data the way the nodes return it
+type dataError struct {
+	message string
+	data    interface{}
+}
+
+func (e dataError) Error() string          { return e.message }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+// withCustomErrors makes the custom errors of the ABI decodable for the test, as the bridge contracts revert
+// with the reason strings only
+func withCustomErrors(t *testing.T, rawABI string) {
+	contractABI, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := revertABIs
+	revertABIs = append([]*abi.ABI{&contractABI}, original...)
+	t.Cleanup(func() { revertABIs = original })
+}
+
+const customErrorsABI = `[
+	{"type": "error", "name": "InsufficientLiquidity", "inputs": [{"name": "available", "type": "uint256"}]}
+]`
+
+func revertData(t *testing.T, signature string, args abi.Arguments, values ...interface{}) string {
+	packed, err := args.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hexutil.Encode(append(crypto.Keccak256([]byte(signature))[:4], packed...))
+}
+
+func TestRevertReason(t *testing.T) {
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uintType, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withCustomErrors(t, customErrorsABI)
+
+	reverted := revertData(t, "Error(string)", abi.Arguments{{Type: stringType}}, "Hashes: the hash nonce is used")
+	panicked := revertData(t, "Panic(uint256)", abi.Arguments{{Type: uintType}}, big.NewInt(0x11))
+	custom := revertData(t, "InsufficientLiquidity(uint256)", abi.Arguments{{Type: uintType}}, big.NewInt(5))
+
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "error string",
+			err:  dataError{message: "execution reverted", data: reverted},
+			want: "Hashes: the hash nonce is used",
+		},
+		{
+			name: "wrapped error string",
+			err:  errors.Wrap(dataError{message: "execution reverted", data: reverted}, "failed to estimate gas"),
+			want: "Hashes: the hash nonce is used",
+		},
+		{
+			name: "panic code",
+			err:  dataError{message: "execution reverted", data: panicked},
+			want: "panic: 0x11",
+		},
+		{
+			name: "custom error",
+			err:  dataError{message: "execution reverted", data: custom},
+			want: "InsufficientLiquidity[5]",
+		},
+		{
+			name: "custom error with malformed arguments",
+			err:  dataError{message: "execution reverted", data: custom[:10]},
+			want: "InsufficientLiquidity",
+		},
+		{
+			name: "unknown selector",
+			err:  dataError{message: "execution reverted", data: "0xdeadbeef"},
+			want: "unknown revert data 0xdeadbeef",
+		},
+		{
+			name: "no revert data",
+			err:  errors.New("execution reverted: NativeHandler: failed to send eth"),
+			want: "execution reverted: NativeHandler: failed to send eth",
+		},
+		{
+			name: "revert data is not a string",
+			err:  dataError{message: "execution reverted", data: map[string]interface{}{}},
+			want: "execution reverted",
+		},
+		{
+			name: "malformed revert data",
+			err:  dataError{message: "execution reverted", data: "0xzz"},
+			want: "execution reverted",
+		},
+		{
+			name: "revert data shorter than the selector",
+			err:  dataError{message: "execution reverted", data: "0x0102"},
+			want: "execution reverted",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := revertReason(tc.err); got != tc.want {
+				t.Fatalf("got reason %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRevertError(t *testing.T) {
+	uintType, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withCustomErrors(t, customErrorsABI)
+
+	panicked := revertData(t, "Panic(uint256)", abi.Arguments{{Type: uintType}}, big.NewInt(0x11))
+	custom := revertData(t, "InsufficientLiquidity(uint256)", abi.Arguments{{Type: uintType}}, big.NewInt(5))
+
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "already withdrawn", err: errors.New("Hashes: the hash nonce is used"), want: bridge.ErrAlreadyWithdrawn},
+		{name: "invalid signature", err: errors.New("Signers: invalid signature"), want: bridge.ErrInvalidSignature},
+		{name: "invalid params", err: errors.New("ERC20Handler: zero receiver"), want: bridge.ErrInvalidWithdrawParams},
+		{
+			name: "no liquidity",
+			err:  errors.New("ERC20: transfer amount exceeds balance"),
+			want: bridge.ErrInsufficientLiquidity,
+		},
+		{name: "unknown reason", err: errors.New("Ownable: caller is not the owner"), want: bridge.ErrWithdrawReverted},
+		{
+			name: "custom error",
+			err:  dataError{message: "execution reverted", data: custom},
+			want: bridge.ErrWithdrawReverted,
+		},
+		{
+			name: "panic code",
+			err:  dataError{message: "execution reverted", data: panicked},
+			want: bridge.ErrWithdrawReverted,
+		},
+		{
+			name: "undecodable data",
+			err:  dataError{message: "execution reverted", data: "0xzz"},
+			want: bridge.ErrWithdrawReverted,
+		},
+		{
+			name: "unknown selector",
+			err:  dataError{message: "execution reverted", data: "0xdeadbeef"},
+			want: bridge.ErrWithdrawReverted,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errors.Cause(revertError(tc.err)); got != tc.want {
+				t.Fatalf("got error %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	bridgebind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/bridge"
+	facadebind "github.com/rarimo/evm-bridge-contracts/gobind/contracts/interfaces/facade"
+	tokenmanager "github.com/rarimo/rarimo-core/x/tokenmanager/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/rarimo/relayer-svc/internal/utils"
+)
+
+// bridgeABI and facadeABI identify the bridge and the facade events among the receipt logs
+var (
+	bridgeABI = mustParseABIs(bridgebind.BridgeMetaData)[0]
+	facadeABI = mustParseABIs(facadebind.IBridgeFacadeMetaData)[0]
+)
+
+// expectedWithdrawal describes the payout the withdrawal transaction of the transfer has to make
+func expectedWithdrawal(transfer core.TransferDetails) (*bridge.Withdrawal, error) {
+	withdrawal := bridge.Withdrawal{Receiver: common.HexToAddress(transfer.Transfer.Receiver).Hex()}
+
+	amount, err := utils.GetAmountOrDefault(transfer.Transfer.Amount, big.NewInt(1))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid amount", logan.F{"amount": transfer.Transfer.Amount})
+	}
+
+	switch transfer.CollectionData.TokenType {
+	case tokenmanager.Type_NATIVE:
+		withdrawal.Amount = amount.String()
+	case tokenmanager.Type_ERC20:
+		withdrawal.Token = common.HexToAddress(transfer.Transfer.To.Address).Hex()
+		withdrawal.Amount = amount.String()
+	case tokenmanager.Type_ERC721, tokenmanager.Type_ERC1155:
+		tokenID, err := parseTokenID(transfer.Transfer.To.TokenID)
+		if err != nil {
+			return nil, err
+		}
+
+		withdrawal.Token = common.HexToAddress(transfer.Transfer.To.Address).Hex()
+		withdrawal.TokenID = tokenID.String()
+		if transfer.CollectionData.TokenType == tokenmanager.Type_ERC1155 {
+			withdrawal.Amount = amount.String()
+		}
+	default:
+		return nil, errors.Errorf("token type %d is not supported", transfer.CollectionData.TokenType)
+	}
+
+	return &withdrawal, nil
+}
+
+// verifyWithdrawal makes sure the successful transaction has actually paid the transfer out, that is the transfer
+// origin is marked as used by the bridge, the bridge emitted the withdrawal event with the expected params and
+// the facade did not move any funds on its own. The client is the one that returned the receipt, so the bridge
+// state is read at the receipt block of the same endpoint.
+func (b *evmBridger) verifyWithdrawal(
+	ctx context.Context,
+	chain *config.EVMChain,
+	client *ethclient.Client,
+	submission *bridge.Submission,
+	receipt *types.Receipt,
+) error {
+	bridgeAddress, err := b.bridgeAddress(chain)
+	if err != nil {
+		return err
+	}
+
+	withdrawn, err := usedAt(ctx, client, bridgeAddress, submission.Origin, receipt.BlockNumber)
+	if err != nil {
+		return err
+	}
+	if !withdrawn {
+		return b.withdrawalMismatch(submission, receipt, logan.F{"reason": "origin hash is not used"})
+	}
+
+	if events := facadeEvents(chain.BridgeFacadeAddress, receipt.Logs); len(events) != 0 {
+		return b.withdrawalMismatch(submission, receipt, logan.F{
+			"reason": "facade emitted the unexpected events",
+			"events": events,
+		})
+	}
+
+	if submission.Expected == nil {
+		return nil
+	}
+
+	actual, err := findWithdrawal(bridgeAddress, submission.Origin, receipt.Logs)
+	if err != nil {
+		return err
+	}
+	if actual == nil {
+		return b.withdrawalMismatch(submission, receipt, logan.F{"reason": "withdrawal event is missing"})
+	}
+	if *actual != *submission.Expected {
+		return b.withdrawalMismatch(submission, receipt, logan.F{
+			"reason":   "withdrawal event does not match the transfer",
+			"expected": *submission.Expected,
+			"actual":   *actual,
+		})
+	}
+
+	return nil
+}
+
+// usedAt tells whether the transfer origin is marked as used by the bridge at the block, the withdrawal is
+// pending while the endpoint has not reached the block yet
+func usedAt(ctx context.Context, client *ethclient.Client, bridgeAddress common.Address, origin string, block *big.Int) (bool, error) {
+	caller, err := bridgebind.NewBridgeCaller(bridgeAddress, client)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to make an instance of ethereum bridger")
+	}
+
+	used, err := caller.UsedHashes(&bind.CallOpts{Context: ctx, BlockNumber: block}, utils.ToByte32(hexutil.MustDecode(origin)))
+	if err == nil {
+		return used, nil
+	}
+
+	head, headErr := client.BlockNumber(ctx)
+	if headErr == nil && head < block.Uint64() {
+		return false, bridge.ErrPending
+	}
+
+	return false, errors.Wrap(err, "failed to check if the transfer was withdrawn", logan.F{"block_number": block})
+}
+
+// facadeEvents returns the names of the facade events among the logs, the facade only forwards the withdrawal to
+// the bridge, so any of its fee token events means the transaction did more than the withdrawal
+func facadeEvents(facadeAddress common.Address, logs []*types.Log) []string {
+	var events []string
+	for _, log := range logs {
+		if log.Address != facadeAddress || len(log.Topics) == 0 {
+			continue
+		}
+
+		// the events unknown to the facade interface are left to the newer contract versions
+		event, err := facadeABI.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		events = append(events, event.Name)
+	}
+
+	return events
+}
+
+// findWithdrawal returns the payout of the bridge withdrawal event of the transfer, nil if there is none
+func findWithdrawal(bridgeAddress common.Address, origin string, logs []*types.Log) (*bridge.Withdrawal, error) {
+	filterer, err := bridgebind.NewBridgeFilterer(bridgeAddress, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make an instance of the bridge filterer")
+	}
+
+	originHash := utils.ToByte32(hexutil.MustDecode(origin))
+	for _, log := range logs {
+		if log.Address != bridgeAddress || len(log.Topics) == 0 {
+			continue
+		}
+
+		event, err := bridgeABI.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		var (
+			withdrawal bridge.Withdrawal
+			eventHash  [32]byte
+		)
+		switch event.Name {
+		case "WithdrawnNative":
+			parsed, err := filterer.ParseWithdrawnNative(*log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse the withdrawal event")
+			}
+			eventHash = parsed.OriginHash
+			withdrawal = bridge.Withdrawal{Receiver: parsed.Receiver.Hex(), Amount: parsed.Amount.String()}
+		case "WithdrawnERC20":
+			parsed, err := filterer.ParseWithdrawnERC20(*log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse the withdrawal event")
+			}
+			eventHash = parsed.OriginHash
+			withdrawal = bridge.Withdrawal{
+				Receiver: parsed.Receiver.Hex(),
+				Token:    parsed.Token.Hex(),
+				Amount:   parsed.Amount.String(),
+			}
+		case "WithdrawnERC721":
+			parsed, err := filterer.ParseWithdrawnERC721(*log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse the withdrawal event")
+			}
+			eventHash = parsed.OriginHash
+			withdrawal = bridge.Withdrawal{
+				Receiver: parsed.Receiver.Hex(),
+				Token:    parsed.Token.Hex(),
+				TokenID:  parsed.TokenId.String(),
+			}
+		case "WithdrawnERC1155":
+			parsed, err := filterer.ParseWithdrawnERC1155(*log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse the withdrawal event")
+			}
+			eventHash = parsed.OriginHash
+			withdrawal = bridge.Withdrawal{
+				Receiver: parsed.Receiver.Hex(),
+				Token:    parsed.Token.Hex(),
+				TokenID:  parsed.TokenId.String(),
+				Amount:   parsed.Amount.String(),
+			}
+		default:
+			continue
+		}
+
+		if eventHash == originHash {
+			return &withdrawal, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// withdrawalMismatch alerts about the transaction that did not withdraw the transfer as requested
+func (b *evmBridger) withdrawalMismatch(submission *bridge.Submission, receipt *types.Receipt, fields logan.F) error {
+	metrics.WithdrawalMismatches.WithLabelValues(submission.Chain).Inc()
+
+	fields = fields.Merge(logan.F{
+		"op_id":    submission.Origin,
+		"to_chain": submission.Chain,
+		"tx_id":    receipt.TxHash,
+	})
+	b.log.WithFields(fields).Error("included withdrawal does not match the transfer")
+
+	return errors.From(bridge.ErrWithdrawalMismatch, fields)
+}
@@ -0,0 +1,212 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+const testOrigin = "0x1111111111111111111111111111111111111111111111111111111111111111"
+
+var (
+	testBridge   = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	testFacade   = common.HexToAddress("0x00000000000000000000000000000000000000fa")
+	testReceiver = common.HexToAddress("0x52908400098527886E0F7030069857D2E4169EE7")
+	testToken    = common.HexToAddress("0x8617E340B3D01FA5F11F306F4090FD50E238070D")
+)
+
+func eventLog(t *testing.T, address common.Address, name string, values ...interface{}) *types.Log {
+	contractABI := bridgeABI
+	if address == testFacade {
+		contractABI = facadeABI
+	}
+
+	event := contractABI.Events[name]
+	data, err := event.Inputs.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &types.Log{Address: address, Topics: []common.Hash{event.ID}, Data: data}
+}
+
+func withdrawnERC20(t *testing.T, address common.Address, origin string, amount int64) *types.Log {
+	return eventLog(t, address, "WithdrawnERC20", testToken, big.NewInt(amount), [32]byte{}, []byte{},
+		common.HexToHash(origin), testReceiver, []byte{}, false)
+}
+
+func TestFindWithdrawal(t *testing.T) {
+	otherOrigin := "0x2222222222222222222222222222222222222222222222222222222222222222"
+
+	cases := []struct {
+		name string
+		logs []*types.Log
+		want *bridge.Withdrawal
+	}{
+		{
+			name: "withdrawal event of the transfer",
+			logs: []*types.Log{withdrawnERC20(t, testBridge, testOrigin, 5)},
+			want: &bridge.Withdrawal{Receiver: testReceiver.Hex(), Token: testToken.Hex(), Amount: "5"},
+		},
+		{
+			name: "withdrawal event of the other transfer",
+			logs: []*types.Log{withdrawnERC20(t, testBridge, otherOrigin, 5)},
+		},
+		{
+			name: "withdrawal event emitted by the other contract",
+			logs: []*types.Log{withdrawnERC20(t, testToken, testOrigin, 5)},
+		},
+		{
+			name: "unrelated logs are skipped",
+			logs: []*types.Log{
+				{Address: testBridge},
+				{Address: testBridge, Topics: []common.Hash{common.HexToHash("0x01")}},
+				withdrawnERC20(t, testBridge, testOrigin, 7),
+			},
+			want: &bridge.Withdrawal{Receiver: testReceiver.Hex(), Token: testToken.Hex(), Amount: "7"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := findWithdrawal(testBridge, testOrigin, tc.logs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got == nil) != (tc.want == nil) || got != nil && *got != *tc.want {
+				t.Fatalf("got withdrawal %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFacadeEvents(t *testing.T) {
+	cases := []struct {
+		name string
+		logs []*types.Log
+		want []string
+	}{
+		{
+			name: "withdrawal forwarded to the bridge",
+			logs: []*types.Log{withdrawnERC20(t, testBridge, testOrigin, 5)},
+		},
+		{
+			name: "fee token withdrawn by the facade",
+			logs: []*types.Log{
+				withdrawnERC20(t, testBridge, testOrigin, 5),
+				eventLog(t, testFacade, "WithdrawnFeeToken", testReceiver, testToken, big.NewInt(5)),
+			},
+			want: []string{"WithdrawnFeeToken"},
+		},
+		{
+			name: "unknown facade events are skipped",
+			logs: []*types.Log{{Address: testFacade, Topics: []common.Hash{common.HexToHash("0x01")}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := facadeEvents(testFacade, tc.logs)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got events %v, want %v", got, tc.want)
+			}
+			for i := range tc.want {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got events %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+// fakeNode answers the bridge calls at the block the way the node does, the calls above its head fail
+type fakeNode struct {
+	head uint64
+	used bool
+}
+
+func (n fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch req.Method {
+	case "eth_blockNumber":
+		resp["result"] = hexutil.Uint64(n.head)
+	case "eth_call":
+		var block hexutil.Uint64
+		if len(req.Params) < 2 || json.Unmarshal(req.Params[1], &block) != nil || uint64(block) > n.head {
+			resp["error"] = map[string]interface{}{"code": -32000, "message": "header not found"}
+			break
+		}
+
+		result := make([]byte, 32)
+		if n.used {
+			result[31] = 1
+		}
+		resp["result"] = hexutil.Bytes(result)
+	default:
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func TestUsedAt(t *testing.T) {
+	cases := []struct {
+		name    string
+		node    fakeNode
+		block   int64
+		want    bool
+		wantErr error
+	}{
+		{name: "used", node: fakeNode{head: 10, used: true}, block: 10, want: true},
+		{name: "not used", node: fakeNode{head: 10}, block: 8},
+		{name: "endpoint behind the receipt block", node: fakeNode{head: 9, used: true}, block: 10, wantErr: bridge.ErrPending},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.node)
+			defer server.Close()
+
+			client, err := ethclient.Dial(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			got, err := usedAt(context.Background(), client, testBridge, testOrigin, big.NewInt(tc.block))
+			if tc.wantErr != nil {
+				if errors.Cause(err) != tc.wantErr {
+					t.Fatalf("got error %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got used %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
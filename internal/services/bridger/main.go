@@ -1,7 +1,9 @@
 package bridger
 
 import (
-	"fmt"
+	"context"
+	"strings"
+
 	"github.com/rarimo/relayer-svc/internal/services/bridger/near"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/rarimo"
 	"sync"
@@ -10,12 +12,16 @@ import (
 	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/evm"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/solana"
+	"github.com/rarimo/relayer-svc/internal/signer"
 	"github.com/rarimo/relayer-svc/internal/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
 type BridgerProvider interface {
-	// GetBridger returns the bridger for the given chain
-	GetBridger(chain string) bridge.Bridger
+	// GetBridger returns the bridger for the given chain, bridge.ErrUnknownChain is returned for the chains
+	// that are not configured
+	GetBridger(chain string) (bridge.Bridger, error)
 }
 
 type bridgerProvider struct {
@@ -31,28 +37,68 @@ func NewBridgerProvider(cfg config.Config) BridgerProvider {
 	}
 }
 
-func (p *bridgerProvider) GetBridger(chain string) bridge.Bridger {
+func (p *bridgerProvider) GetBridger(chain string) (bridge.Bridger, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	if bridger, ok := p.bridgers[chain]; ok {
-		return bridger
+		return bridger, nil
+	}
+
+	if !p.cfg.Chains().Has(chain) {
+		return nil, errors.From(bridge.ErrUnknownChain, logan.F{"chain": chain})
+	}
+
+	family, ok := p.cfg.Chains().Family(chain)
+	if !ok {
+		// the family is not discovered from the core params yet
+		return nil, errors.From(bridge.ErrChainUnavailable, logan.F{"chain": chain})
 	}
 
 	var bridger bridge.Bridger
-	switch {
-	case types.IsEVM(chain):
+	switch family {
+	case types.FamilyEVM:
 		bridger = evm.NewEVMBridger(p.cfg)
-	case chain == types.Solana:
+	case types.FamilySolana:
 		bridger = solana.NewSolanaBridger(p.cfg)
-	case chain == types.Near:
+	case types.FamilyNear:
 		bridger = near.NewNearBridger(p.cfg)
-	case chain == types.Rarimo:
+	case types.FamilyRarimo:
 		bridger = rarimo.NewRarimoBridger(p.cfg)
 	default:
-		panic(fmt.Errorf("unknown chain %s", chain))
+		return nil, errors.From(bridge.ErrUnknownChain, logan.F{"chain": chain, "family": family})
 	}
+	bridger = withBreaker(p.cfg, chain, family, bridger)
 	p.bridgers[chain] = bridger
 
-	return bridger
+	return bridger, nil
+}
+
+// CheckSigners makes sure the signer has the keys of every configured chain, so the instance serving a subset of
+// the chains needs the keys of those chains only. The chains of the undeclared family are checked once their
+// bridger is created.
+func CheckSigners(ctx context.Context, cfg config.Config) error {
+	missing := make([]string, 0)
+	for _, chain := range cfg.Chains().Names() {
+		family := cfg.Chains().DeclaredFamily(chain)
+		if family == "" {
+			continue
+		}
+
+		keys, err := cfg.Signer().Keys(ctx, signer.SetOf(chain, family))
+		if err != nil {
+			return errors.Wrap(err, "failed to get the signer keys", logan.F{"chain": chain})
+		}
+		if len(keys) == 0 {
+			missing = append(missing, chain)
+		}
+	}
+
+	if len(missing) != 0 {
+		return errors.From(errors.New("no signer keys for the configured chains"), logan.F{
+			"chains": strings.Join(missing, ", "),
+		})
+	}
+
+	return nil
 }
@@ -9,40 +9,55 @@ import (
 	"github.com/rarimo/relayer-svc/internal/config"
 	"github.com/rarimo/relayer-svc/internal/data/core"
 	"github.com/rarimo/relayer-svc/internal/data/horizon"
+	"github.com/rarimo/relayer-svc/internal/nonce"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/rarimo/relayer-svc/internal/signer"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"github.com/rarimo/relayer-svc/internal/tracing"
 	"github.com/rarimo/relayer-svc/internal/utils"
-	"github.com/rarimo/relayer-svc/pkg/secret"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"lukechampine.com/uint128"
 	"math/big"
+	"strings"
+	"time"
 )
 
 type nearBridger struct {
-	log     *logan.Entry
-	near    *config.Near
-	vault   secret.Vault
-	horizon horizon.Horizon
+	log      *logan.Entry
+	near     *config.Near
+	networks *config.Networks
+	signer   signer.Signer
+	horizon  horizon.Horizon
+	signers  *signerpool.Pool
+	nonces   nonce.Allocator
 }
 
 func NewNearBridger(cfg config.Config) bridge.Bridger {
 	return &nearBridger{
-		log:     cfg.Log().WithField("service", "near_bridge"),
-		near:    cfg.Near(),
-		vault:   cfg.Vault(),
-		horizon: cfg.Horizon(),
+		log:      cfg.Log().WithField("service", "near_bridge"),
+		near:     cfg.Near(),
+		networks: cfg.Networks(),
+		signer:   cfg.Signer(),
+		horizon:  cfg.Horizon(),
+		signers:  cfg.SignerPool(),
+		nonces:   cfg.Nonces(),
 	}
 }
 
-func (b *nearBridger) Withdraw(
+func (b *nearBridger) Submit(
 	ctx context.Context,
 	transfer core.TransferDetails,
-) error {
+) (_ *bridge.Submission, err error) {
+	ctx, span := tracing.Start(ctx, "near.Submit", attribute.String("op_id", transfer.Origin))
+	defer func() { tracing.End(span, err) }()
+
 	log := b.log.WithField("op_id", transfer.Origin)
 
 	amount, err := parseNearAmount(transfer.Transfer.Amount)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse amount")
+		return nil, errors.Wrap(err, "failed to parse amount")
 	}
 	rawSignature := hexutil.MustDecode(transfer.Signature)
 	signature := hexutil.Encode(rawSignature[:64])
@@ -93,7 +108,7 @@ func (b *nearBridger) Withdraw(
 				transfer.Transfer.To.TokenID,
 			)
 			if err != nil {
-				return errors.Wrap(err, "failed to get NFT metadata")
+				return nil, errors.Wrap(err, "failed to get NFT metadata")
 			}
 
 			args.TokenMetadata = toNearNftMetadata(metadata, transfer.Item.Meta)
@@ -102,31 +117,252 @@ func (b *nearBridger) Withdraw(
 
 		act = common.NewNftWithdrawCall(args, common.DefaultFunctionCallGas, deposit)
 	default:
-		return errors.Errorf("invalid near token type: %d", transfer.CollectionData.TokenType)
+		return nil, errors.Errorf("invalid near token type: %d", transfer.CollectionData.TokenType)
+	}
+
+	bridgeAddress, err := b.bridgeAddress(transfer.Transfer.To.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	client, done, err := b.near.RPC.Acquire()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the rpc client")
+	}
+	defer func() { done(err) }()
+
+	key, err := b.selectSigner(ctx, transfer.Transfer.To.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := common.WrapRawKey(common.KeyTypeED25519, key.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signer public key")
+	}
+
+	// the nonce is tracked per access key, so the concurrent withdrawals signed by the key do not reuse it
+	account := nonce.Account(transfer.Transfer.To.Chain, publicKey.ToBase58PublicKey().Value)
+	txNonce, err := b.nonces.Next(ctx, account, func(ctx context.Context) (uint64, error) {
+		accessKey, err := client.AccessKeyView(ctx, key.Address, publicKey.ToBase58PublicKey(), nearclient.FinalityFinal())
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get the access key", logan.F{"signer": key.Address})
+		}
+
+		return accessKey.Nonce + 1, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to allocate a nonce")
+	}
+
+	sent := false
+	defer func() {
+		settleErr := nonce.Settle(ctx, b.nonces, account, txNonce, sent, err != nil && isNonceError(err))
+		if settleErr != nil {
+			log.WithError(settleErr).WithFields(logan.F{"account": account, "nonce": txNonce}).Error("failed to settle the nonce")
+		}
+	}()
+
+	blob, err := b.signTx(ctx, client, key, publicKey, txNonce, bridgeAddress, act)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign a Near transaction")
+	}
+
+	txHash, err := client.RPCTransactionSend(ctx, blob)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to submit a Near transaction")
+	}
+	sent = true
+
+	log.WithFields(logan.F{"tx_id": txHash.String(), "signer": key.Address}).Info("successfully submitted Near transaction")
+
+	return &bridge.Submission{
+		Chain:       transfer.Transfer.To.Chain,
+		Origin:      transfer.Origin,
+		TxHash:      txHash.String(),
+		Signer:      key.Address,
+		SubmittedAt: time.Now().UTC(),
+	}, nil
+}
+
+// selectSigner picks the access key of the signers pool for the new withdrawal
+func (b *nearBridger) selectSigner(ctx context.Context, chain string) (signer.Key, error) {
+	keys, err := b.signer.Keys(ctx, signer.SetNear)
+	if err != nil {
+		return signer.Key{}, errors.Wrap(err, "failed to get the signer keys")
+	}
+	if len(keys) == 0 {
+		return signer.Key{}, errors.New("no signer keys configured")
+	}
+
+	selected, err := b.signers.Select(ctx, chain, signer.Addresses(keys))
+	if err != nil {
+		return signer.Key{}, errors.Wrap(err, "failed to select the signer")
+	}
+
+	return signer.Find(keys, selected)
+}
+
+// signTx builds the transaction calling the bridge on behalf of the key account and signs it through the
+// signer backend, the serialized transaction is returned
+func (b *nearBridger) signTx(
+	ctx context.Context,
+	client *nearclient.Client,
+	key signer.Key,
+	publicKey common.PublicKey,
+	txNonce uint64,
+	bridgeAddress common.AccountID,
+	act common.Action,
+) (string, error) {
+	block, err := client.BlockDetails(ctx, nearclient.FinalityFinal())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the latest block")
+	}
+
+	txn := common.Transaction{
+		SignerID:   key.Address,
+		PublicKey:  publicKey,
+		Nonce:      txNonce,
+		ReceiverID: bridgeAddress,
+		BlockHash:  block.Header.Hash,
+		Actions:    []common.Action{act},
+	}
+
+	txHash, _, err := txn.Hash()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to hash the transaction")
+	}
+
+	signature, err := b.signer.SignMessage(ctx, signer.SetNear, key.Address, txHash[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign the transaction", logan.F{"signer": key.Address})
+	}
+
+	blob, err := common.SignedTransaction{
+		Transaction: txn,
+		Signature:   common.NewSignatureED25519(signature),
+	}.Serialize()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to serialize the signed transaction")
+	}
+
+	return blob, nil
+}
+
+func (b *nearBridger) Confirm(ctx context.Context, submission *bridge.Submission) (*bridge.Result, error) {
+	txHash, err := common.NewCryptoHashFromBase58(submission.TxHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction hash")
 	}
 
-	withdrawResp, err := b.near.RPC.TransactionSendAwait(
-		nearclient.ContextWithKeyPair(ctx, b.vault.Secret().Near().PrivateKey()),
-		b.vault.Secret().Near().PublicKey(),
-		b.near.BridgeAddress,
-		[]common.Action{act},
-		nearclient.WithLatestBlock(),
-	)
+	var outcome common.FinalExecutionOutcomeView
+	err = b.near.RPC.Do(func(client *nearclient.Client) (err error) {
+		outcome, err = client.TransactionStatus(ctx, txHash, submission.Signer)
+		return err
+	})
+	if isUnknownTx(err) {
+		return nil, bridge.ErrPending
+	}
 	if err != nil {
-		return errors.Wrap(err, "failed to submit a Near transaction")
+		return nil, errors.Wrap(err, "failed to get the transaction status")
 	}
-	if len(withdrawResp.Status.Failure) != 0 {
-		log.
-			WithField("tx_id", withdrawResp.Transaction.Hash).
-			WithField("status_failure", utils.Prettify(withdrawResp.Status.Failure)).
+
+	fields := logan.F{"op_id": submission.Origin, "tx_id": submission.TxHash}
+	if len(outcome.Status.Failure) != 0 {
+		b.log.
+			WithFields(fields).
+			WithField("status_failure", utils.Prettify(outcome.Status.Failure)).
 			Info("near transaction failed")
 
-		return errors.New("near transaction failed")
+		return nil, errors.From(bridge.ErrTxFailed, fields)
+	}
+
+	var block common.BlockView
+	err = b.near.RPC.Do(func(client *nearclient.Client) (err error) {
+		block, err = client.BlockDetails(ctx, nearclient.BlockHash(outcome.TransactionOutcome.BlockHash))
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the transaction block", fields)
+	}
+
+	// the transaction is paid by the gas burnt for itself and for all the receipts it produced
+	gasUsed := outcome.TransactionOutcome.Outcome.GasBurnt
+	fee := outcome.TransactionOutcome.Outcome.TokensBurnt.Big()
+	for _, receipt := range outcome.ReceiptsOutcome {
+		gasUsed += receipt.Outcome.GasBurnt
+		fee.Add(fee, receipt.Outcome.TokensBurnt.Big())
 	}
 
-	log.WithField("tx_id", withdrawResp.Transaction.Hash).Info("successfully submitted Near transaction")
+	var price *big.Int
+	if gasUsed != 0 {
+		price = new(big.Int).Div(fee, new(big.Int).SetUint64(gasUsed))
+	}
+
+	b.log.WithFields(fields.Merge(logan.F{
+		"block_height": block.Header.Height,
+		"gas_used":     gasUsed,
+	})).Info("near transaction confirmed")
+
+	return &bridge.Result{
+		Chain:          submission.Chain,
+		TxHash:         submission.TxHash,
+		Block:          uint64(block.Header.Height),
+		GasUsed:        gasUsed,
+		EffectivePrice: price,
+		Fee:            fee,
+		Signer:         submission.Signer,
+		ConfirmedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// isUnknownTx tells whether the node has not seen the transaction yet
+// isNonceError tells whether the node rejected the transaction as its nonce is already used by the access key
+func isNonceError(err error) bool {
+	return strings.Contains(err.Error(), "InvalidNonce")
+}
+
+func isUnknownTx(err error) bool {
+	rpcErr, ok := errors.Cause(err).(*common.JsonRpcError)
+	return ok && rpcErr.Cause.Name == "UNKNOWN_TRANSACTION"
+}
+
+func (b *nearBridger) SignerBalances(ctx context.Context, _ string) ([]bridge.Balance, error) {
+	keys, err := b.signer.Keys(ctx, signer.SetNear)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the signer keys")
+	}
+
+	balances := make([]bridge.Balance, len(keys))
+	for i, key := range keys {
+		var account common.AccountView
+		err := b.near.RPC.Do(func(client *nearclient.Client) (err error) {
+			account, err = client.AccountView(ctx, key.Address, nearclient.FinalityFinal())
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the signer account", logan.F{"signer": key.Address})
+		}
+
+		balances[i] = bridge.Balance{Signer: key.Address, Amount: account.Amount.Big()}
+	}
+
+	return balances, nil
+}
+
+// bridgeAddress prefers the bridge account registered in the core params to the configured one
+func (b *nearBridger) bridgeAddress(chain string) (common.AccountID, error) {
+	if network, ok := b.networks.Get(chain); ok && network.Contract != "" {
+		return network.Contract, nil
+	}
+
+	if b.near.BridgeAddress == "" {
+		return "", errors.From(errors.New("bridge address is neither discovered nor configured"), logan.F{
+			"chain": chain,
+		})
+	}
 
-	return nil
+	return b.near.BridgeAddress, nil
 }
 
 func parseNearAmount(raw string) (common.Balance, error) {
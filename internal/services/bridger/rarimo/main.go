@@ -2,65 +2,93 @@ package rarimo
 
 import (
 	"context"
+	"crypto/sha256"
 	clientypes "github.com/cosmos/cosmos-sdk/client"
-	clienttx "github.com/cosmos/cosmos-sdk/client/tx"
 	"github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	"github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	client "github.com/cosmos/cosmos-sdk/types/tx"
 	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	xauthsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	bridgetypes "github.com/rarimo/rarimo-core/x/bridge/types"
 	tokenmanager "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"github.com/rarimo/relayer-svc/internal/config"
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/nonce"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
-	"github.com/rarimo/relayer-svc/pkg/secret"
+	"github.com/rarimo/relayer-svc/internal/signer"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"github.com/rarimo/relayer-svc/internal/tracing"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math/big"
+	"time"
 )
 
 type rarimoBridger struct {
 	log      *logan.Entry
-	vault    secret.Vault
+	signer   signer.Signer
 	rarimo   *config.Rarimo
 	txConfig clientypes.TxConfig
 	auth     authtypes.QueryClient
 	tx       sdktx.ServiceClient
+	bank     banktypes.QueryClient
+	signers  *signerpool.Pool
+	nonces   nonce.Allocator
 }
 
 func NewRarimoBridger(cfg config.Config) bridge.Bridger {
 	return &rarimoBridger{
 		log:      cfg.Log().WithField("service", "rarimo_bridge"),
-		vault:    cfg.Vault(),
+		signer:   cfg.Signer(),
 		rarimo:   cfg.Rarimo(),
 		txConfig: tx.NewTxConfig(codec.NewProtoCodec(codectypes.NewInterfaceRegistry()), []signing.SignMode{signing.SignMode_SIGN_MODE_DIRECT}),
 		auth:     authtypes.NewQueryClient(cfg.Cosmos()),
 		tx:       sdktx.NewServiceClient(cfg.Cosmos()),
+		bank:     banktypes.NewQueryClient(cfg.Cosmos()),
+		signers:  cfg.SignerPool(),
+		nonces:   cfg.Nonces(),
 	}
 }
 
-func (b *rarimoBridger) Withdraw(
+func (b *rarimoBridger) Submit(
 	ctx context.Context,
 	transfer core.TransferDetails,
-) error {
+) (_ *bridge.Submission, err error) {
+	ctx, span := tracing.Start(ctx, "rarimo.Submit", attribute.String("op_id", transfer.Origin))
+	defer func() { tracing.End(span, err) }()
+
 	f := logan.F{"op_id": transfer.Origin}
 
 	if transfer.CollectionData.TokenType != tokenmanager.Type_NATIVE {
-		return errors.From(errors.New("only native tokens are supported"), f)
+		return nil, errors.From(errors.New("only native tokens are supported"), f)
+	}
+	key, err := b.selectSigner(ctx, transfer.Transfer.To.Chain)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select the signer", f)
 	}
+	address := key.Address
+	f["signer"] = address
+	pubKey := &secp256k1.PubKey{Key: key.PublicKey}
+
 	builder := b.txConfig.NewTxBuilder()
-	address := b.vault.Secret().Rarimo().PublicKey()
 
-	err := builder.SetMsgs(&bridgetypes.MsgWithdrawNative{
+	err = builder.SetMsgs(&bridgetypes.MsgWithdrawNative{
 		Creator: address,
 		Origin:  transfer.Origin,
 	})
 	if err != nil {
-		return errors.Wrap(err, "failed to set withdraw message to the tx builder", f)
+		return nil, errors.Wrap(err, "failed to set withdraw message to the tx builder", f)
 	}
 
 	builder.SetGasLimit(b.rarimo.GasLimit)
@@ -68,19 +96,35 @@ func (b *rarimoBridger) Withdraw(
 
 	accountResp, err := b.auth.Account(ctx, &authtypes.QueryAccountRequest{Address: address})
 	if err != nil {
-		return errors.Wrap(err, "failed to get account", f)
+		return nil, errors.Wrap(err, "failed to get account", f)
 	}
 
 	account := authtypes.BaseAccount{}
 	err = account.Unmarshal(accountResp.Account.Value)
 	if err != nil {
-		return errors.Wrap(err, "failed to unmarshal account", f)
+		return nil, errors.Wrap(err, "failed to unmarshal account", f)
+	}
+
+	// the sequence is tracked per key, as the account one is not increased until the previous transaction is
+	// included, so the concurrent withdrawals signed by the key would reuse it
+	nonceAccount := nonce.Account(transfer.Transfer.To.Chain, address)
+	accountSequence, err := b.nonces.Next(ctx, nonceAccount, func(context.Context) (uint64, error) {
+		return account.GetSequence(), nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to allocate the sequence", f)
 	}
 
-	accountSequence := account.GetSequence()
+	var sent, rejected bool
+	defer func() {
+		settleErr := nonce.Settle(ctx, b.nonces, nonceAccount, accountSequence, sent, rejected)
+		if settleErr != nil {
+			b.log.WithError(settleErr).WithFields(f.Merge(logan.F{"sequence": accountSequence})).Error("failed to settle the sequence")
+		}
+	}()
 
 	err = builder.SetSignatures(signing.SignatureV2{
-		PubKey: b.vault.Secret().Rarimo().PrivateKey().PubKey(),
+		PubKey: pubKey,
 		Data: &signing.SingleSignatureData{
 			SignMode:  b.txConfig.SignModeHandler().DefaultMode(),
 			Signature: nil,
@@ -88,7 +132,7 @@ func (b *rarimoBridger) Withdraw(
 		Sequence: accountSequence,
 	})
 	if err != nil {
-		return errors.Wrap(err, "failed to set signature to the tx builder", f)
+		return nil, errors.Wrap(err, "failed to set signature to the tx builder", f)
 	}
 
 	signerData := xauthsigning.SignerData{
@@ -97,35 +141,148 @@ func (b *rarimoBridger) Withdraw(
 		Sequence:      accountSequence,
 	}
 
-	sigV2, err := clienttx.SignWithPrivKey(
-		b.txConfig.SignModeHandler().DefaultMode(), signerData,
-		builder, b.vault.Secret().Rarimo().PrivateKey(), b.txConfig, accountSequence,
+	signBytes, err := b.txConfig.SignModeHandler().GetSignBytes(
+		b.txConfig.SignModeHandler().DefaultMode(), signerData, builder.GetTx(),
 	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the bytes to sign", f)
+	}
 
-	err = builder.SetSignatures(sigV2)
+	// the cosmos secp256k1 signature is the [R || S] one over the sha256 of the sign bytes
+	digest := sha256.Sum256(signBytes)
+	signature, err := b.signer.SignDigest(ctx, signer.SetRarimo, address, digest[:])
 	if err != nil {
-		return errors.Wrap(err, "failed to set signature v2 to the tx builder", f)
+		return nil, errors.Wrap(err, "failed to sign the transaction", f)
+	}
+
+	err = builder.SetSignatures(signing.SignatureV2{
+		PubKey: pubKey,
+		Data: &signing.SingleSignatureData{
+			SignMode:  b.txConfig.SignModeHandler().DefaultMode(),
+			Signature: signature[:64],
+		},
+		Sequence: accountSequence,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set signature v2 to the tx builder", f)
 	}
 
 	tx, err := b.txConfig.TxEncoder()(builder.GetTx())
 	if err != nil {
-		return errors.Wrap(err, "failed to encode tx", f)
+		return nil, errors.Wrap(err, "failed to encode tx", f)
 	}
 
 	resp, err := b.tx.BroadcastTx(
 		ctx,
 		&client.BroadcastTxRequest{
-			Mode:    client.BroadcastMode_BROADCAST_MODE_BLOCK,
+			Mode:    client.BroadcastMode_BROADCAST_MODE_SYNC,
 			TxBytes: tx,
 		},
 	)
 	if err != nil {
-		return errors.Wrap(err, "failed to broadcast tx", f)
+		return nil, errors.Wrap(err, "failed to broadcast tx", f)
+	}
+	if resp.TxResponse.Code != abci.CodeTypeOK {
+		rejected = resp.TxResponse.Codespace == sdkerrors.RootCodespace &&
+			resp.TxResponse.Code == sdkerrors.ErrWrongSequence.ABCICode()
+
+		return nil, errors.From(errors.New("tx was rejected by the mempool"), f.Merge(logan.F{
+			"code":    resp.TxResponse.Code,
+			"raw_log": resp.TxResponse.RawLog,
+		}))
 	}
 
+	sent = true
+
 	b.log.WithFields(f.Merge(logan.F{
 		"tx_id": resp.TxResponse.TxHash,
 	})).Info("successfully submitted Rarimo transaction")
 
-	return nil
+	return &bridge.Submission{
+		Chain:       transfer.Transfer.To.Chain,
+		Origin:      transfer.Origin,
+		TxHash:      resp.TxResponse.TxHash,
+		Signer:      address,
+		SubmittedAt: time.Now().UTC(),
+	}, nil
+}
+
+func (b *rarimoBridger) Confirm(ctx context.Context, submission *bridge.Submission) (*bridge.Result, error) {
+	f := logan.F{"op_id": submission.Origin, "tx_id": submission.TxHash}
+
+	resp, err := b.tx.GetTx(ctx, &client.GetTxRequest{Hash: submission.TxHash})
+	if status.Code(errors.Cause(err)) == codes.NotFound {
+		return nil, bridge.ErrPending
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get tx", f)
+	}
+
+	if resp.TxResponse.Code != abci.CodeTypeOK {
+		return nil, errors.From(bridge.ErrTxFailed, f.Merge(logan.F{
+			"code":    resp.TxResponse.Code,
+			"raw_log": resp.TxResponse.RawLog,
+		}))
+	}
+
+	// the cosmos transactions pay the whole fee set regardless of the gas used
+	fee := new(big.Int)
+	if resp.Tx != nil && resp.Tx.AuthInfo != nil && resp.Tx.AuthInfo.Fee != nil {
+		fee = resp.Tx.AuthInfo.Fee.Amount.AmountOf(b.rarimo.Coin).BigInt()
+	}
+
+	var price *big.Int
+	if resp.TxResponse.GasWanted > 0 {
+		price = new(big.Int).Div(fee, big.NewInt(resp.TxResponse.GasWanted))
+	}
+
+	b.log.WithFields(f.Merge(logan.F{"height": resp.TxResponse.Height})).Info("rarimo transaction confirmed")
+
+	return &bridge.Result{
+		Chain:          submission.Chain,
+		TxHash:         submission.TxHash,
+		Block:          uint64(resp.TxResponse.Height),
+		GasUsed:        uint64(resp.TxResponse.GasUsed),
+		EffectivePrice: price,
+		Fee:            fee,
+		Signer:         submission.Signer,
+		ConfirmedAt:    time.Now().UTC(),
+	}, nil
+}
+
+func (b *rarimoBridger) SignerBalances(ctx context.Context, _ string) ([]bridge.Balance, error) {
+	keys, err := b.signer.Keys(ctx, signer.SetRarimo)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the signer keys")
+	}
+
+	balances := make([]bridge.Balance, len(keys))
+	for i, key := range keys {
+		resp, err := b.bank.Balance(ctx, &banktypes.QueryBalanceRequest{Address: key.Address, Denom: b.rarimo.Coin})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the signer balance", logan.F{"signer": key.Address})
+		}
+
+		balances[i] = bridge.Balance{Signer: key.Address, Amount: resp.Balance.Amount.BigInt()}
+	}
+
+	return balances, nil
+}
+
+// selectSigner picks the key of the signers pool for the new withdrawal
+func (b *rarimoBridger) selectSigner(ctx context.Context, chain string) (signer.Key, error) {
+	keys, err := b.signer.Keys(ctx, signer.SetRarimo)
+	if err != nil {
+		return signer.Key{}, errors.Wrap(err, "failed to get the signer keys")
+	}
+	if len(keys) == 0 {
+		return signer.Key{}, errors.New("no signer keys configured")
+	}
+
+	selected, err := b.signers.Select(ctx, chain, signer.Addresses(keys))
+	if err != nil {
+		return signer.Key{}, err
+	}
+
+	return signer.Find(keys, selected)
 }
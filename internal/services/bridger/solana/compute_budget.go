@@ -0,0 +1,154 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/pkg/errors"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+const (
+	// the instruction discriminators of the compute budget program
+	setComputeUnitLimitInstruction = 2
+	setComputeUnitPriceInstruction = 3
+
+	microLamportsPerLamport = 1_000_000
+)
+
+var computeBudgetProgramID = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+
+type prioritizationFee struct {
+	Slot              uint64 `json:"slot"`
+	PrioritizationFee uint64 `json:"prioritizationFee"`
+}
+
+// withComputeBudget prepends the compute budget instructions to the withdrawal, the unit price is taken from
+// the fees recently paid for the bridge accounts and the unit limit from the simulation of the withdrawal
+func (b *solanaBridger) withComputeBudget(
+	ctx context.Context,
+	client *rpc.Client,
+	instruction solana.Instruction,
+	payer solana.PublicKey,
+	table *lookupTable,
+	resubmissions int,
+) ([]solana.Instruction, error) {
+	budget := b.solana.ComputeBudget
+
+	price, err := b.unitPrice(ctx, client, instruction, resubmissions)
+	if err != nil {
+		return nil, err
+	}
+
+	// the simulation runs with the highest limit, so the withdrawal does not run out of the units
+	units, err := b.simulate(ctx, client, []solana.Instruction{
+		setComputeUnitLimit(budget.MaxLimit),
+		setComputeUnitPrice(price),
+		instruction,
+	}, payer, table)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := budget.Limit(units, resubmissions)
+	if budget.MaxPriorityFee != 0 && limit != 0 {
+		maxPrice := budget.MaxPriorityFee * microLamportsPerLamport / uint64(limit)
+		if price > maxPrice {
+			price = maxPrice
+		}
+	}
+
+	b.log.WithFields(logan.F{
+		"simulated_units": units,
+		"unit_limit":      limit,
+		"unit_price":      price,
+		"resubmissions":   resubmissions,
+	}).Debug("compute budget of the withdrawal")
+
+	return []solana.Instruction{setComputeUnitLimit(limit), setComputeUnitPrice(price), instruction}, nil
+}
+
+// unitPrice returns the compute unit price in micro-lamports, ErrFeeTooHigh is returned while the market
+// price exceeds the cap
+func (b *solanaBridger) unitPrice(
+	ctx context.Context,
+	client *rpc.Client,
+	instruction solana.Instruction,
+	resubmissions int,
+) (uint64, error) {
+	budget := b.solana.ComputeBudget
+
+	// the fees are paid for the write locks, so only the writable accounts compete with the withdrawal
+	accounts := make([]string, 0)
+	for _, account := range instruction.Accounts() {
+		if account.IsWritable {
+			accounts = append(accounts, account.PublicKey.String())
+		}
+	}
+
+	var fees []prioritizationFee
+	if err := client.RPCCallForInto(ctx, &fees, "getRecentPrioritizationFees", []interface{}{accounts}); err != nil {
+		return 0, errors.Wrap(err, "failed to get the recent prioritization fees")
+	}
+
+	market := feePercentile(fees, budget.FeePercentile)
+	if budget.MaxUnitPrice != 0 && market > budget.MaxUnitPrice {
+		return 0, errors.Wrapf(bridge.ErrFeeTooHigh, "compute unit price %d is above the cap %d", market, budget.MaxUnitPrice)
+	}
+	if market < budget.MinUnitPrice {
+		market = budget.MinUnitPrice
+	}
+
+	escalated := budget.Escalate(float64(market), resubmissions)
+	if escalated > math.MaxInt64 {
+		escalated = math.MaxInt64
+	}
+
+	price := uint64(escalated)
+	if budget.MaxUnitPrice != 0 && price > budget.MaxUnitPrice {
+		price = budget.MaxUnitPrice
+	}
+
+	return price, nil
+}
+
+// feePercentile returns the percentile of the fees paid in the recent slots
+func feePercentile(fees []prioritizationFee, percentile float64) uint64 {
+	if len(fees) == 0 {
+		return 0
+	}
+
+	values := make([]uint64, len(fees))
+	for i, fee := range fees {
+		values[i] = fee.PrioritizationFee
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	idx := int(math.Ceil(percentile/100*float64(len(values)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	return values[idx]
+}
+
+func setComputeUnitLimit(units uint32) solana.Instruction {
+	data := make([]byte, 5)
+	data[0] = setComputeUnitLimitInstruction
+	binary.LittleEndian.PutUint32(data[1:], units)
+
+	return solana.NewInstruction(computeBudgetProgramID, solana.AccountMetaSlice{}, data)
+}
+
+func setComputeUnitPrice(microLamports uint64) solana.Instruction {
+	data := make([]byte, 9)
+	data[0] = setComputeUnitPriceInstruction
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+
+	return solana.NewInstruction(computeBudgetProgramID, solana.AccountMetaSlice{}, data)
+}
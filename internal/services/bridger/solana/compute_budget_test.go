@@ -0,0 +1,38 @@
+package solana
+
+import (
+	"testing"
+)
+
+func TestFeePercentile(t *testing.T) {
+	fees := func(values ...uint64) []prioritizationFee {
+		result := make([]prioritizationFee, len(values))
+		for i, value := range values {
+			result[i] = prioritizationFee{Slot: uint64(i), PrioritizationFee: value}
+		}
+
+		return result
+	}
+
+	cases := []struct {
+		name       string
+		fees       []prioritizationFee
+		percentile float64
+		want       uint64
+	}{
+		{name: "no fees", percentile: 75, want: 0},
+		{name: "single fee", fees: fees(10), percentile: 75, want: 10},
+		{name: "median", fees: fees(30, 10, 20), percentile: 50, want: 20},
+		{name: "upper percentile", fees: fees(40, 10, 30, 20), percentile: 75, want: 30},
+		{name: "highest", fees: fees(40, 10, 30, 20), percentile: 100, want: 40},
+		{name: "zero percentile", fees: fees(40, 10, 30, 20), percentile: 0, want: 10},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := feePercentile(tc.fees, tc.percentile); got != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
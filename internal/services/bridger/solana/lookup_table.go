@@ -0,0 +1,296 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+const (
+	// the instruction discriminators of the address lookup table program
+	createLookupTableInstruction = 0
+	extendLookupTableInstruction = 2
+
+	// lookupTableMetaSize is the size of the table state preceding the addresses
+	lookupTableMetaSize = 56
+	maxLookupTableSize  = 256
+	// maxExtendAddresses keeps the extension transaction within the size limit
+	maxExtendAddresses = 20
+	// lookupTableTimeout is the time the created table or the extended addresses are awaited for before
+	// they are requested again
+	lookupTableTimeout = 2 * time.Minute
+)
+
+var addressLookupTableProgramID = solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+// pendingLookupTable is the managed table created but not seen in the chain yet, the address is zero while
+// the creation transaction is being sent
+type pendingLookupTable struct {
+	address   solana.PublicKey
+	createdAt time.Time
+}
+
+// lookupTable returns the table the withdrawal loads the accounts from, nil is returned while there is no table.
+// The managed table is created and extended with the shared accounts in background, so the withdrawal does not
+// wait for them. The table failures are not fatal, as the withdrawal may fit into the transaction without it.
+func (b *solanaBridger) lookupTable(
+	ctx context.Context,
+	client *rpc.Client,
+	chain string,
+	shared []solana.PublicKey,
+) *lookupTable {
+	cfg := b.solana.LookupTable
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	log := b.log.WithField("chain", chain)
+	address := cfg.Address
+	if address.IsZero() {
+		var err error
+		if address, err = b.managedTable(ctx, client, chain); err != nil {
+			log.WithError(err).Warn("failed to get the managed lookup table")
+			return nil
+		}
+		if address.IsZero() {
+			return nil
+		}
+	}
+
+	table, err := loadLookupTable(ctx, client, address)
+	if err != nil {
+		log.WithError(err).WithField("table", address.String()).Warn("failed to load the lookup table")
+		return nil
+	}
+
+	if cfg.Managed {
+		if err = b.extendLookupTable(ctx, client, table, shared); err != nil {
+			log.WithError(err).WithField("table", address.String()).Warn("failed to extend the lookup table")
+		}
+	}
+
+	return table
+}
+
+// managedTable returns the table created by the relayer, the zero address is returned while it is being created
+func (b *solanaBridger) managedTable(ctx context.Context, client *rpc.Client, chain string) (solana.PublicKey, error) {
+	stored, err := b.redis.LookupTable(ctx, chain)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	if stored != "" {
+		return solana.PublicKeyFromBase58(stored)
+	}
+
+	b.tableMu.Lock()
+	pending := b.pendingTable
+	if pending == nil {
+		// the creation is claimed, so the concurrent withdrawals do not create the tables of their own
+		b.pendingTable = &pendingLookupTable{createdAt: time.Now()}
+	}
+	b.tableMu.Unlock()
+
+	if pending == nil {
+		address, err := b.createLookupTable(ctx, client)
+
+		b.tableMu.Lock()
+		b.pendingTable = nil
+		if err == nil {
+			b.pendingTable = &pendingLookupTable{address: address, createdAt: time.Now()}
+		}
+		b.tableMu.Unlock()
+
+		return solana.PublicKey{}, err
+	}
+	if pending.address.IsZero() {
+		return solana.PublicKey{}, nil
+	}
+
+	_, err = loadLookupTable(ctx, client, pending.address)
+	switch {
+	case errors.Cause(err) == rpc.ErrNotFound && time.Since(pending.createdAt) > lookupTableTimeout:
+		// the creation transaction was dropped, the table is created again by the next withdrawal
+		b.tableMu.Lock()
+		if b.pendingTable == pending {
+			b.pendingTable = nil
+		}
+		b.tableMu.Unlock()
+
+		return solana.PublicKey{}, nil
+	case err != nil:
+		return solana.PublicKey{}, nil
+	}
+
+	stored, err = b.redis.SetLookupTable(ctx, chain, pending.address.String())
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+
+	b.tableMu.Lock()
+	if b.pendingTable == pending {
+		b.pendingTable = nil
+	}
+	b.tableMu.Unlock()
+
+	return solana.PublicKeyFromBase58(stored)
+}
+
+func (b *solanaBridger) createLookupTable(ctx context.Context, client *rpc.Client) (solana.PublicKey, error) {
+	authority := b.solana.LookupTable.Authority
+
+	// the table address is derived from the recent slot, which has to be known to the cluster
+	slot, err := client.GetSlot(ctx, rpc.CommitmentFinalized)
+	if err != nil {
+		return solana.PublicKey{}, errors.Wrap(err, "failed to get the slot")
+	}
+
+	slotBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(slotBytes, slot)
+	address, bump, err := solana.FindProgramAddress([][]byte{authority[:], slotBytes}, addressLookupTableProgramID)
+	if err != nil {
+		return solana.PublicKey{}, errors.Wrap(err, "failed to derive the lookup table address")
+	}
+
+	data := make([]byte, 13)
+	binary.LittleEndian.PutUint32(data, createLookupTableInstruction)
+	binary.LittleEndian.PutUint64(data[4:], slot)
+	data[12] = bump
+
+	if err = b.sendTableTx(ctx, client, address, data); err != nil {
+		return solana.PublicKey{}, errors.Wrap(err, "failed to create the lookup table")
+	}
+
+	b.log.WithField("table", address.String()).Info("creating the lookup table")
+
+	return address, nil
+}
+
+// extendLookupTable adds the shared accounts missing in the table, they are used once the extension is included
+func (b *solanaBridger) extendLookupTable(
+	ctx context.Context,
+	client *rpc.Client,
+	table *lookupTable,
+	shared []solana.PublicKey,
+) error {
+	missing := b.claimMissing(table, shared)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	data := make([]byte, 12, 12+len(missing)*len(solana.PublicKey{}))
+	binary.LittleEndian.PutUint32(data, extendLookupTableInstruction)
+	binary.LittleEndian.PutUint64(data[4:], uint64(len(missing)))
+	for _, address := range missing {
+		data = append(data, address[:]...)
+	}
+
+	if err := b.sendTableTx(ctx, client, table.address, data); err != nil {
+		// the addresses are extended by the next withdrawal
+		b.tableMu.Lock()
+		for _, address := range missing {
+			delete(b.extending, address)
+		}
+		b.tableMu.Unlock()
+
+		return err
+	}
+
+	b.log.WithFields(logan.F{"table": table.address.String(), "addresses": len(missing)}).Info("extending the lookup table")
+
+	return nil
+}
+
+// claimMissing returns the shared accounts the table lacks and marks them as being extended, so the concurrent
+// withdrawals do not add them twice
+func (b *solanaBridger) claimMissing(table *lookupTable, shared []solana.PublicKey) []solana.PublicKey {
+	known := make(map[solana.PublicKey]bool, len(table.addresses))
+	for _, address := range table.addresses {
+		known[address] = true
+	}
+
+	b.tableMu.Lock()
+	defer b.tableMu.Unlock()
+
+	missing := make([]solana.PublicKey, 0)
+	for _, address := range shared {
+		extendedAt, extending := b.extending[address]
+		if known[address] || (extending && time.Since(extendedAt) < lookupTableTimeout) {
+			continue
+		}
+		if len(missing) == maxExtendAddresses || len(table.addresses)+len(missing) == maxLookupTableSize {
+			break
+		}
+
+		missing = append(missing, address)
+		b.extending[address] = time.Now()
+	}
+
+	return missing
+}
+
+// sendTableTx sends the lookup table instruction paid and signed by the table authority
+func (b *solanaBridger) sendTableTx(ctx context.Context, client *rpc.Client, table solana.PublicKey, data []byte) error {
+	authority := b.solana.LookupTable.Authority
+	instruction := solana.NewInstruction(addressLookupTableProgramID, solana.AccountMetaSlice{
+		solana.Meta(table).WRITE(),
+		solana.Meta(authority).SIGNER(),
+		solana.Meta(authority).SIGNER().WRITE(),
+		solana.Meta(solana.SystemProgramID),
+	}, data)
+
+	recent, err := client.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch recent blockhash")
+	}
+
+	tx, err := solana.NewTransaction([]solana.Instruction{instruction}, recent.Value.Blockhash, solana.TransactionPayer(authority))
+	if err != nil {
+		return errors.Wrap(err, "failed to form the lookup table transaction")
+	}
+	if err = b.signTx(ctx, tx, authority); err != nil {
+		return errors.Wrap(err, "failed to sign the lookup table transaction")
+	}
+
+	if _, err = client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{PreflightCommitment: rpc.CommitmentConfirmed}); err != nil {
+		return errors.Wrap(err, "failed to send the lookup table transaction")
+	}
+
+	return nil
+}
+
+// loadLookupTable reads the activated addresses of the table, the ones added in the slot the state is read
+// at can not be loaded by the transactions yet
+func loadLookupTable(ctx context.Context, client *rpc.Client, address solana.PublicKey) (*lookupTable, error) {
+	account, err := client.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the lookup table account")
+	}
+
+	data := account.Value.Data.GetBinary()
+	if !account.Value.Owner.Equals(addressLookupTableProgramID) || len(data) < lookupTableMetaSize {
+		return nil, errors.New("account is not a lookup table")
+	}
+	if deactivationSlot := binary.LittleEndian.Uint64(data[4:12]); deactivationSlot != math.MaxUint64 {
+		return nil, errors.Errorf("lookup table is deactivated at slot %d", deactivationSlot)
+	}
+
+	addresses := make([]solana.PublicKey, 0, (len(data)-lookupTableMetaSize)/len(solana.PublicKey{}))
+	for offset := lookupTableMetaSize; offset+len(solana.PublicKey{}) <= len(data); offset += len(solana.PublicKey{}) {
+		addresses = append(addresses, solana.PublicKeyFromBytes(data[offset:offset+len(solana.PublicKey{})]))
+	}
+
+	lastExtendedSlot, startIndex := binary.LittleEndian.Uint64(data[12:20]), int(data[20])
+	if lastExtendedSlot >= account.Context.Slot && startIndex <= len(addresses) {
+		addresses = addresses[:startIndex]
+	}
+
+	return &lookupTable{address: address, addresses: addresses}, nil
+}
@@ -2,28 +2,46 @@ package solana
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/olegfomenko/solana-go"
 	"github.com/olegfomenko/solana-go/rpc"
-	confirm "github.com/olegfomenko/solana-go/rpc/sendAndConfirmTransaction"
 	"github.com/pkg/errors"
 	tokenmanager "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"github.com/rarimo/relayer-svc/internal/config"
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/failover"
 	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/rarimo/relayer-svc/internal/signer"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"github.com/rarimo/relayer-svc/internal/tracing"
 	"github.com/rarimo/relayer-svc/internal/utils"
-	"github.com/rarimo/relayer-svc/pkg/secret"
 	solanabridge "github.com/rarimo/solana-program-go/contracts/bridge"
 	"gitlab.com/distributed_lab/logan/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"math/big"
+	"sync"
+	"time"
 )
 
+// maxBlockhashRebuilds bounds the rebuilds of the transaction which blockhash expired before it was sent
+const maxBlockhashRebuilds = 3
+
 type solanaBridger struct {
 	log          *logan.Entry
 	tokenmanager tokenmanager.QueryClient
 	solana       *config.Solana
-	vault        secret.Vault
+	networks     *config.Networks
+	signer       signer.Signer
+	signers      *signerpool.Pool
+	redis        redis.Rediser
+
+	// tableMu guards the state of the managed lookup table
+	tableMu      sync.Mutex
+	pendingTable *pendingLookupTable
+	extending    map[solana.PublicKey]time.Time
 }
 
 func NewSolanaBridger(cfg config.Config) bridge.Bridger {
@@ -31,46 +49,314 @@ func NewSolanaBridger(cfg config.Config) bridge.Bridger {
 		log:          cfg.Log().WithField("service", "solana_bridger"),
 		tokenmanager: tokenmanager.NewQueryClient(cfg.Cosmos()),
 		solana:       cfg.Solana(),
-		vault:        cfg.Vault(),
+		networks:     cfg.Networks(),
+		signer:       cfg.Signer(),
+		signers:      cfg.SignerPool(),
+		redis:        cfg.Redis(),
+		extending:    make(map[solana.PublicKey]time.Time),
 	}
 }
 
-func (b *solanaBridger) Withdraw(
+func (b *solanaBridger) Submit(
 	ctx context.Context,
 	transfer core.TransferDetails,
-) error {
+) (_ *bridge.Submission, err error) {
+	ctx, span := tracing.Start(ctx, "solana.Submit", attribute.String("op_id", transfer.Origin))
+	defer func() { tracing.End(span, err) }()
+
 	log := b.log.WithField("op_id", transfer.Origin)
-	withdrawn, err := b.isAlreadyWithdrawn(ctx, transfer)
+	withdrawn, err := b.isAlreadyWithdrawn(ctx, transfer.Transfer.To.Chain, transfer.Origin)
 	if err != nil {
-		return errors.Wrap(err, "failed to check if the transfer is withdrawn")
+		return nil, errors.Wrap(err, "failed to check if the transfer is withdrawn")
 	}
 	if withdrawn {
-		return bridge.ErrAlreadyWithdrawn
+		return nil, bridge.ErrAlreadyWithdrawn
 	}
 
-	tx, err := b.makeWithdrawTx(ctx, transfer)
+	rpcClient, done, err := b.solana.RPC.Acquire()
 	if err != nil {
-		return errors.Wrap(err, "failed to call the withdraw method")
+		return nil, errors.Wrap(err, "failed to get the rpc client")
 	}
-	sig, err := confirm.SendAndConfirmTransaction(
-		ctx,
-		b.solana.RPC,
-		b.solana.WS,
-		tx,
+	defer func() { done(err) }()
+
+	signer, err := b.selectSigner(ctx, transfer.Transfer.To.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sig             solana.Signature
+		lastValidHeight uint64
 	)
+	// the blockhash may expire while the transaction is being signed, e.g. by the remote signer, so the
+	// transaction is built again with the fresh one
+	for rebuilds := 0; ; rebuilds++ {
+		var tx *withdrawTx
+		tx, err = b.makeWithdrawTx(ctx, rpcClient, signer, transfer)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to call the withdraw method")
+		}
+
+		lastValidHeight = tx.lastValidHeight
+		sig, err = b.sendTx(ctx, rpcClient, tx)
+		if errors.Cause(err) != errBlockhashExpired || rebuilds == maxBlockhashRebuilds || ctx.Err() != nil {
+			break
+		}
+
+		log.WithError(err).Warn("blockhash expired before the transaction was sent, rebuilding it")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(logan.F{"sig": sig.String(), "signer": signer.String()}).Info("successfully submitted transaction")
+
+	return &bridge.Submission{
+		Chain:           transfer.Transfer.To.Chain,
+		Origin:          transfer.Origin,
+		TxHash:          sig.String(),
+		Signer:          signer.String(),
+		LastValidHeight: lastValidHeight,
+		SubmittedAt:     time.Now().UTC(),
+	}, nil
+}
+
+func (b *solanaBridger) Confirm(ctx context.Context, submission *bridge.Submission) (*bridge.Result, error) {
+	sig, err := solana.SignatureFromBase58(submission.TxHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction signature")
+	}
+
+	var statuses *rpc.GetSignatureStatusesResult
+	err = b.solana.RPC.Do(func(client *rpc.Client) (err error) {
+		statuses, err = client.GetSignatureStatuses(ctx, true, sig)
+		return err
+	})
 	if err != nil {
-		return errors.Wrap(err, "failed to submit a solana transaction")
+		return nil, errors.Wrap(err, "failed to get the signature status")
+	}
+
+	if len(statuses.Value) == 0 || statuses.Value[0] == nil {
+		return nil, b.checkExpired(ctx, submission)
 	}
 
-	log.WithFields(logan.F{"sig": sig.String()}).Info("successfully submitted transaction")
+	status := statuses.Value[0]
+	if status.Err != nil {
+		return nil, b.failedTxError(ctx, submission, sig, status.Err)
+	}
 
+	switch status.ConfirmationStatus {
+	case rpc.ConfirmationStatusConfirmed, rpc.ConfirmationStatusFinalized:
+	default:
+		return nil, bridge.ErrPending
+	}
+
+	tx, err := b.confirmedTx(ctx, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := new(big.Int)
+	if tx != nil && tx.Meta != nil {
+		fee.SetUint64(tx.Meta.Fee)
+	}
+
+	b.log.WithFields(logan.F{
+		"op_id": submission.Origin,
+		"sig":   submission.TxHash,
+		"slot":  status.Slot,
+		"fee":   fee,
+	}).Info("solana transaction confirmed")
+
+	return &bridge.Result{
+		Chain:       submission.Chain,
+		TxHash:      submission.TxHash,
+		Block:       status.Slot,
+		Fee:         fee,
+		Signer:      submission.Signer,
+		ConfirmedAt: time.Now().UTC(),
+	}, nil
+}
+
+// failedTxError decodes the logs of the included transaction that failed the same way the simulation failures are
+// decoded, the transfer withdrawn by the other transaction in the meantime is reported as such
+func (b *solanaBridger) failedTxError(
+	ctx context.Context,
+	submission *bridge.Submission,
+	sig solana.Signature,
+	txErr interface{},
+) error {
+	withdrawn, err := b.isAlreadyWithdrawn(ctx, submission.Chain, submission.Origin)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the transfer was withdrawn")
+	}
+	if withdrawn {
+		return errors.Wrapf(bridge.ErrAlreadyWithdrawn, "transaction %s failed", submission.TxHash)
+	}
+
+	tx, err := b.confirmedTx(ctx, sig)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrapf(includedTxError(txErr, tx), "transaction %s failed", submission.TxHash)
+}
+
+// includedTxError maps the failure of the included transaction to the typed error by its logs, the failures not
+// caused by the transfer are reported as ErrTxFailed
+func includedTxError(txErr interface{}, tx *confirmedTx) error {
+	if tx == nil || tx.Meta == nil || tx.Transaction == nil {
+		if computeExceeded(txErr) {
+			// the limit is raised for the resubmitted transfer
+			return errors.Wrapf(bridge.ErrOutOfGas, "withdrawal exceeded the compute budget: %s", errorString(txErr))
+		}
+
+		return errors.Wrapf(bridge.ErrTxFailed, "transaction error %s", errorString(txErr))
+	}
+
+	// the withdrawal is the last instruction of the transaction
+	withdrawal := len(tx.Transaction.Message.Instructions) - 1
+	decoded := transactionError(txErr, tx.Meta.LogMessages, withdrawal)
+	if bridge.IsPermanent(decoded) || bridge.IsTransient(decoded) || errors.Cause(decoded) == bridge.ErrAlreadyWithdrawn {
+		return decoded
+	}
+
+	return errors.Wrapf(bridge.ErrTxFailed, "transaction error %s: %s", errorString(txErr), programLog(tx.Meta.LogMessages))
+}
+
+// confirmedTx returns the included transaction with its meta, nil if the node does not know it
+func (b *solanaBridger) confirmedTx(ctx context.Context, sig solana.Signature) (*confirmedTx, error) {
+	// the client does not request the versioned transactions, so the transaction is read on its own
+	var tx *confirmedTx
+	err := b.solana.RPC.Do(func(client *rpc.Client) error {
+		return client.RPCCallForInto(ctx, &tx, "getTransaction", []interface{}{sig.String(), map[string]interface{}{
+			"commitment":                     rpc.CommitmentConfirmed,
+			"encoding":                       solana.EncodingJSON,
+			"maxSupportedTransactionVersion": 0,
+		}})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the confirmed transaction")
+	}
+
+	return tx, nil
+}
+
+// checkExpired tells whether the unknown transaction can still be included, as it is dropped by the
+// cluster once its blockhash is too old
+func (b *solanaBridger) checkExpired(ctx context.Context, submission *bridge.Submission) error {
+	var height uint64
+	err := b.solana.RPC.Do(func(client *rpc.Client) (err error) {
+		height, err = client.GetBlockHeight(ctx, rpc.CommitmentConfirmed)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get the block height")
+	}
+
+	if height > submission.LastValidHeight {
+		return errors.Wrapf(bridge.ErrTxDropped, "blockhash of the transaction %s expired at height %d",
+			submission.TxHash, submission.LastValidHeight)
+	}
+
+	return bridge.ErrPending
+}
+
+func (b *solanaBridger) SignerBalances(ctx context.Context, _ string) ([]bridge.Balance, error) {
+	keys, err := b.signer.Keys(ctx, signer.SetSolana)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the signer keys")
+	}
+
+	balances := make([]bridge.Balance, len(keys))
+	for i, key := range keys {
+		var balance *rpc.GetBalanceResult
+		err := b.solana.RPC.Do(func(client *rpc.Client) (err error) {
+			balance, err = client.GetBalance(ctx, solana.PublicKeyFromBytes(key.PublicKey), rpc.CommitmentFinalized)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the signer %s balance", key.Address)
+		}
+
+		balances[i] = bridge.Balance{Signer: key.Address, Amount: new(big.Int).SetUint64(balance.Value)}
+	}
+
+	return balances, nil
+}
+
+// selectSigner picks the key of the signers pool paying for the new withdrawal
+func (b *solanaBridger) selectSigner(ctx context.Context, chain string) (solana.PublicKey, error) {
+	keys, err := b.signer.Keys(ctx, signer.SetSolana)
+	if err != nil {
+		return solana.PublicKey{}, errors.Wrap(err, "failed to get the signer keys")
+	}
+	if len(keys) == 0 {
+		return solana.PublicKey{}, errors.New("no signer keys configured")
+	}
+
+	selected, err := b.signers.Select(ctx, chain, signer.Addresses(keys))
+	if err != nil {
+		return solana.PublicKey{}, errors.Wrap(err, "failed to select the signer")
+	}
+
+	return solana.MustPublicKeyFromBase58(selected), nil
+}
+
+// signTx signs the transaction paid by the payer through the signer backend, the payer is its only signer
+func (b *solanaBridger) signTx(ctx context.Context, tx *solana.Transaction, payer solana.PublicKey) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the message for signing")
+	}
+
+	signature, err := b.signer.SignMessage(ctx, signer.SetSolana, payer.String(), message)
+	if err != nil {
+		return errors.Wrapf(err, "failed to sign with the key %s", payer)
+	}
+
+	tx.Signatures = []solana.Signature{solana.SignatureFromBytes(signature)}
 	return nil
 }
 
+// signMessage signs the v0 message paid by the payer and returns the encoded transaction with its signature
+func (b *solanaBridger) signMessage(ctx context.Context, message []byte, payer solana.PublicKey) ([]byte, solana.Signature, error) {
+	signature, err := b.signer.SignMessage(ctx, signer.SetSolana, payer.String(), message)
+	if err != nil {
+		return nil, solana.Signature{}, errors.Wrapf(err, "failed to sign with the key %s", payer)
+	}
+
+	sig := solana.SignatureFromBytes(signature)
+	return encodeTransaction(message, sig), sig, nil
+}
+
+// withdrawTx is the signed v0 transaction of the withdrawal
+type withdrawTx struct {
+	raw             []byte
+	sig             solana.Signature
+	instructions    int
+	lastValidHeight uint64
+}
+
+// confirmedTx is the part of the getTransaction result the relayer needs
+type confirmedTx struct {
+	Transaction *struct {
+		Message struct {
+			Instructions []json.RawMessage `json:"instructions"`
+		} `json:"message"`
+	} `json:"transaction"`
+	Meta *struct {
+		Fee         uint64   `json:"fee"`
+		LogMessages []string `json:"logMessages"`
+	} `json:"meta"`
+}
+
 func (b *solanaBridger) makeWithdrawTx(
 	ctx context.Context,
+	client *rpc.Client,
+	signer solana.PublicKey,
 	transfer core.TransferDetails,
-) (*solana.Transaction, error) {
+) (*withdrawTx, error) {
 	receiver := hexutil.MustDecode(transfer.Transfer.Receiver)
 	origin := utils.ToByte32(hexutil.MustDecode(transfer.Origin))
 	signature := hexutil.MustDecode(transfer.Signature)
@@ -88,7 +374,12 @@ func (b *solanaBridger) makeWithdrawTx(
 		Signature:  utils.ToByte64(signature),
 	}
 
-	withdrawAddress, _, err := solana.FindProgramAddress([][]byte{origin[:]}, b.solana.BridgeProgramID)
+	programID, bridgeAdmin, err := b.bridgeAccounts(transfer.Transfer.To.Chain)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the bridge accounts")
+	}
+
+	withdrawAddress, _, err := solana.FindProgramAddress([][]byte{origin[:]}, programID)
 	if err != nil {
 		return nil, errors.New("failed to create withdraw address")
 	}
@@ -110,8 +401,8 @@ func (b *solanaBridger) makeWithdrawTx(
 	switch transfer.CollectionData.TokenType {
 	case tokenmanager.Type_NATIVE:
 		instruction, err = solanabridge.WithdrawNativeInstruction(
-			b.solana.BridgeProgramID,
-			b.solana.BridgeAdmin,
+			programID,
+			bridgeAdmin,
 			solana.PublicKeyFromBytes(receiver),
 			withdrawAddress,
 			args,
@@ -119,8 +410,8 @@ func (b *solanaBridger) makeWithdrawTx(
 	case tokenmanager.Type_METAPLEX_FT:
 		tokenAddress := hexutil.MustDecode(transfer.Transfer.To.Address)
 		instruction, err = solanabridge.WithdrawFTInstruction(
-			b.solana.BridgeProgramID,
-			b.solana.BridgeAdmin,
+			programID,
+			bridgeAdmin,
 			solana.PublicKeyFromBytes(tokenAddress),
 			solana.PublicKeyFromBytes(receiver),
 			withdrawAddress,
@@ -129,8 +420,8 @@ func (b *solanaBridger) makeWithdrawTx(
 	case tokenmanager.Type_METAPLEX_NFT:
 		tokenID := hexutil.MustDecode(transfer.Transfer.To.TokenID)
 		instruction, err = solanabridge.WithdrawNFTInstruction(
-			b.solana.BridgeProgramID,
-			b.solana.BridgeAdmin,
+			programID,
+			bridgeAdmin,
 			solana.PublicKeyFromBytes(tokenID),
 			solana.PublicKeyFromBytes(receiver),
 			withdrawAddress,
@@ -143,49 +434,175 @@ func (b *solanaBridger) makeWithdrawTx(
 		return nil, errors.Wrap(err, "failed to construct the solana instruction")
 	}
 
-	recent, err := b.solana.RPC.GetLatestBlockhash(
-		context.Background(),
-		rpc.CommitmentFinalized,
-	)
+	shared := sharedAccounts(instruction, bridgeAdmin)
+	table := b.lookupTable(ctx, client, transfer.Transfer.To.Chain, shared)
+
+	// the size depends on neither the blockhash nor the compute budget values, so the transaction that can
+	// never be sent is rejected before they are requested
+	instructions := []solana.Instruction{instruction}
+	if !b.solana.ComputeBudget.Disabled {
+		instructions = []solana.Instruction{setComputeUnitLimit(0), setComputeUnitPrice(0), instruction}
+	}
+	if err = b.checkFits(instructions, signer, table, shared); err != nil {
+		return nil, err
+	}
+
+	if b.solana.ComputeBudget.Disabled {
+		if _, err = b.simulate(ctx, client, instructions, signer, table); err != nil {
+			return nil, err
+		}
+	} else {
+		instructions, err = b.withComputeBudget(ctx, client, instruction, signer, table, transfer.Resubmissions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the confirmed blockhash leaves more time for the transaction to be included than the finalized one
+	recent, err := client.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to fetch recent blockhash")
 	}
 
-	tx, err := solana.NewTransaction(
-		[]solana.Instruction{instruction},
-		recent.Value.Blockhash,
-		solana.TransactionPayer(b.vault.Secret().Solana().PublicKey()),
-	)
+	message, err := compileV0(instructions, signer, recent.Value.Blockhash, table)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to form a solana transaction")
+		return nil, errors.Wrap(err, "failed to compile the transaction")
 	}
 
-	if _, err = tx.AddSignature(b.vault.Secret().Solana().PrivateKey()); err != nil {
+	raw, sig, err := b.signMessage(ctx, message, signer)
+	if err != nil {
 		return nil, errors.Wrap(err, "failed to sign a solana transaction")
 	}
 
-	return tx, nil
+	return &withdrawTx{
+		raw:             raw,
+		sig:             sig,
+		instructions:    len(instructions),
+		lastValidHeight: recent.Value.LastValidBlockHeight,
+	}, nil
 }
 
-func (b *solanaBridger) isAlreadyWithdrawn(ctx context.Context, transfer core.TransferDetails) (bool, error) {
-	origin := utils.ToByte32(hexutil.MustDecode(transfer.Origin))
-	withdrawAddress, _, err := solana.FindProgramAddress([][]byte{origin[:]}, b.solana.BridgeProgramID)
+// checkFits makes sure the withdrawal fits into the transaction. While the managed table is being created or
+// extended, the withdrawal that fits once the table holds all the shared accounts is postponed instead of failed.
+func (b *solanaBridger) checkFits(
+	instructions []solana.Instruction,
+	payer solana.PublicKey,
+	table *lookupTable,
+	shared []solana.PublicKey,
+) error {
+	draft, err := compileV0(instructions, payer, solana.Hash{}, table)
+	if err != nil {
+		return errors.Wrap(err, "failed to compile the transaction")
+	}
+
+	sizeErr := checkSize(draft)
+	if sizeErr == nil || !b.solana.LookupTable.Managed {
+		return sizeErr
+	}
+
+	// the address of the table being created is not known yet, any one takes the same space
+	complete := &lookupTable{address: addressLookupTableProgramID}
+	if table != nil {
+		complete.address = table.address
+		complete.addresses = append(complete.addresses, table.addresses...)
+	}
+	known := make(map[solana.PublicKey]bool, len(complete.addresses))
+	for _, address := range complete.addresses {
+		known[address] = true
+	}
+	for _, address := range shared {
+		if !known[address] {
+			complete.addresses = append(complete.addresses, address)
+		}
+	}
+	if len(complete.addresses) > maxLookupTableSize {
+		// the full table is not extended anymore
+		return sizeErr
+	}
+
+	draft, err = compileV0(instructions, payer, solana.Hash{}, complete)
+	if err != nil {
+		return errors.Wrap(err, "failed to compile the transaction")
+	}
+	if err = checkSize(draft); err != nil {
+		return err
+	}
+
+	return errors.Wrap(bridge.ErrTxNotReady, "withdrawal fits once the lookup table holds the shared accounts")
+}
+
+// sharedAccounts returns the accounts of the withdrawal that are likely to be used by the next ones, so they
+// are worth adding to the lookup table: the bridge admin and the read-only ones, e.g. the programs and the mints
+func sharedAccounts(instruction solana.Instruction, bridgeAdmin solana.PublicKey) []solana.PublicKey {
+	shared := []solana.PublicKey{bridgeAdmin}
+	for _, meta := range instruction.Accounts() {
+		if !meta.IsSigner && !meta.IsWritable && !meta.PublicKey.Equals(bridgeAdmin) {
+			shared = append(shared, meta.PublicKey)
+		}
+	}
+
+	return shared
+}
+
+func (b *solanaBridger) isAlreadyWithdrawn(ctx context.Context, chain, transferOrigin string) (bool, error) {
+	origin := utils.ToByte32(hexutil.MustDecode(transferOrigin))
+	programID, _, err := b.bridgeAccounts(chain)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get the bridge accounts")
+	}
+
+	withdrawAddress, _, err := solana.FindProgramAddress([][]byte{origin[:]}, programID)
 	if err != nil {
 		return false, errors.New("failed to create withdraw address")
 	}
-	_, err = b.solana.RPC.GetAccountInfoWithOpts(
-		ctx, withdrawAddress,
-		&rpc.GetAccountInfoOpts{
-			Commitment: rpc.CommitmentType(rpc.ConfirmationStatusProcessed),
-		},
-	)
-	if errors.Cause(err) == rpc.ErrNotFound {
-		// has not been withdrawn yet
-	} else if err != nil {
-		return false, errors.Wrap(err, "failed to get withdraw account")
-	} else {
+
+	return failover.Quorum(ctx, b.solana.RPC, b.solana.Quorum, func(ctx context.Context, client *rpc.Client) (bool, error) {
+		_, err := client.GetAccountInfoWithOpts(
+			ctx, withdrawAddress,
+			&rpc.GetAccountInfoOpts{
+				Commitment: rpc.CommitmentType(rpc.ConfirmationStatusProcessed),
+			},
+		)
+		if errors.Cause(err) == rpc.ErrNotFound {
+			// has not been withdrawn yet
+			return false, nil
+		} else if err != nil {
+			return false, errors.Wrap(err, "failed to get withdraw account")
+		}
+
 		return true, nil
+	})
+}
+
+// bridgeAccounts returns the bridge program ID and admin preferring the ones registered in the core params
+func (b *solanaBridger) bridgeAccounts(chain string) (solana.PublicKey, solana.PublicKey, error) {
+	network, ok := b.networks.Get(chain)
+	if !ok || network.Contract == "" {
+		if b.solana.BridgeProgramID.IsZero() {
+			return solana.PublicKey{}, solana.PublicKey{}, errors.New("bridge program id is neither discovered nor configured")
+		}
+
+		return b.solana.BridgeProgramID, b.solana.BridgeAdmin, nil
+	}
+
+	programID, err := solana.PublicKeyFromBase58(network.Contract)
+	if err != nil {
+		return solana.PublicKey{}, solana.PublicKey{}, errors.Wrap(err, "invalid bridge program id in the core params")
+	}
+
+	if network.Admin != "" {
+		admin, err := solana.PublicKeyFromBase58(network.Admin)
+		if err != nil {
+			return solana.PublicKey{}, solana.PublicKey{}, errors.Wrap(err, "invalid bridge admin in the core params")
+		}
+
+		return programID, admin, nil
+	}
+
+	admin, err := b.solana.DeriveBridgeAdmin(programID)
+	if err != nil {
+		return solana.PublicKey{}, solana.PublicKey{}, errors.Wrap(err, "failed to derive the bridge admin")
 	}
 
-	return false, nil
+	return programID, admin, nil
 }
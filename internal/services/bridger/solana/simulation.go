@@ -0,0 +1,181 @@
+package solana
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/olegfomenko/solana-go/rpc"
+	"github.com/olegfomenko/solana-go/rpc/jsonrpc"
+	"github.com/pkg/errors"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/spf13/cast"
+)
+
+// errBlockhashExpired is returned when the cluster does not know the blockhash of the transaction anymore,
+// so the transaction has to be built again
+var errBlockhashExpired = errors.New("blockhash expired")
+
+// knownLogs map the program logs of the failed withdrawal to the typed errors, the bridge program reports
+// its errors in the logs only, as the custom error codes are not part of its client
+var knownLogs = []struct {
+	substr string
+	err    error
+}{
+	// the withdrawal account of the transfer is created by the first withdrawal
+	{"already in use", bridge.ErrAlreadyWithdrawn},
+	{"wrong signature", bridge.ErrInvalidSignature},
+	{"invalid signature", bridge.ErrInvalidSignature},
+	{"wrong merkle", bridge.ErrInvalidSignature},
+	{"invalid merkle", bridge.ErrInvalidSignature},
+	{"wrong token seed", bridge.ErrInvalidWithdrawParams},
+	{"wrong args", bridge.ErrInvalidWithdrawParams},
+	{"insufficient funds", bridge.ErrInsufficientLiquidity},
+	{"insufficient lamports", bridge.ErrInsufficientLiquidity},
+}
+
+// simulate runs the v0 transaction of the instructions without signing it and returns the consumed compute
+// units, the failure is decoded into the typed error. The withdrawal has to be the last instruction.
+func (b *solanaBridger) simulate(
+	ctx context.Context,
+	client *rpc.Client,
+	instructions []solana.Instruction,
+	payer solana.PublicKey,
+	table *lookupTable,
+) (uint64, error) {
+	// the blockhash is replaced by the node, so the simulation does not depend on its expiry
+	message, err := compileV0(instructions, payer, solana.Hash{}, table)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to form the simulated transaction")
+	}
+	tx := encodeTransaction(message, make([]solana.Signature, message[1])...)
+
+	// the client encodes the legacy transactions only, so the encoded one is passed as is
+	var simulated *rpc.SimulateTransactionResponse
+	err = client.RPCCallForInto(ctx, &simulated, "simulateTransaction", []interface{}{
+		base64.StdEncoding.EncodeToString(tx),
+		map[string]interface{}{
+			"encoding":               solana.EncodingBase64,
+			"commitment":             rpc.CommitmentConfirmed,
+			"replaceRecentBlockhash": true,
+		},
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to simulate the withdrawal")
+	}
+	if simulated == nil || simulated.Value == nil {
+		return 0, errors.New("empty simulation result")
+	}
+	if simulated.Value.Err != nil {
+		return 0, transactionError(simulated.Value.Err, simulated.Value.Logs, len(instructions)-1)
+	}
+	if simulated.Value.UnitsConsumed == nil {
+		return 0, errors.New("simulation result has no consumed units")
+	}
+
+	return *simulated.Value.UnitsConsumed, nil
+}
+
+// sendTx sends the signed transaction, the node simulates it once again before forwarding it to the leaders
+func (b *solanaBridger) sendTx(ctx context.Context, client *rpc.Client, tx *withdrawTx) (solana.Signature, error) {
+	_, err := client.SendEncodedTransactionWithOpts(ctx, base64.StdEncoding.EncodeToString(tx.raw), rpc.TransactionOpts{
+		PreflightCommitment: rpc.CommitmentConfirmed,
+	})
+	if err == nil {
+		return tx.sig, nil
+	}
+
+	rpcErr, ok := errors.Cause(err).(*jsonrpc.RPCError)
+	if !ok {
+		return solana.Signature{}, errors.Wrap(err, "failed to submit a solana transaction")
+	}
+
+	// the preflight failure carries the simulation result
+	if data, ok := rpcErr.Data.(map[string]interface{}); ok && data["err"] != nil {
+		logs, _ := cast.ToStringSliceE(data["logs"])
+		return solana.Signature{}, transactionError(data["err"], logs, tx.instructions-1)
+	}
+	if strings.Contains(strings.ToLower(rpcErr.Message), "blockhash not found") {
+		return solana.Signature{}, errors.Wrap(errBlockhashExpired, rpcErr.Message)
+	}
+
+	return solana.Signature{}, errors.Wrap(err, "failed to submit a solana transaction")
+}
+
+// transactionError maps the error of the failed transaction to the typed one, only the failures of the withdrawal
+// instruction are caused by the transfer, the rest can be fixed by retrying
+func transactionError(txErr interface{}, logs []string, withdrawal int) error {
+	reason := errorString(txErr)
+
+	switch value := txErr.(type) {
+	case string:
+		if value == "BlockhashNotFound" {
+			return errors.Wrap(errBlockhashExpired, "transaction simulation failed")
+		}
+
+		return errors.Errorf("transaction simulation failed with %s", reason)
+	case map[string]interface{}:
+		instructionErr, ok := value["InstructionError"].([]interface{})
+		if !ok || len(instructionErr) != 2 {
+			return errors.Errorf("transaction simulation failed with %s", reason)
+		}
+
+		if idx, err := cast.ToIntE(instructionErr[0]); err != nil || idx != withdrawal {
+			return errors.Errorf("compute budget instruction failed with %s", reason)
+		}
+		if computeExceeded(value) {
+			// the withdrawal is parked, as it may fit into the budget once the bridge state changes
+			return errors.Wrapf(bridge.ErrOutOfGas, "withdrawal exceeded the compute budget: %s", reason)
+		}
+
+		for _, known := range knownLogs {
+			for _, line := range logs {
+				if strings.Contains(strings.ToLower(line), known.substr) {
+					return errors.Wrapf(known.err, "withdrawal failed with %s: %s", reason, line)
+				}
+			}
+		}
+
+		return errors.Wrapf(bridge.ErrWithdrawReverted, "withdrawal failed with %s: %s", reason, programLog(logs))
+	default:
+		return errors.Errorf("transaction simulation failed with %s", reason)
+	}
+}
+
+// computeExceeded tells whether the transaction failed as it ran out of the compute units
+func computeExceeded(txErr interface{}) bool {
+	value, ok := txErr.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	instructionErr, ok := value["InstructionError"].([]interface{})
+	return ok && len(instructionErr) == 2 && instructionErr[1] == "ComputationalBudgetExceeded"
+}
+
+// programLog returns the last message logged by the program, which is usually the error reported by it
+func programLog(logs []string) string {
+	for i := len(logs) - 1; i >= 0; i-- {
+		if strings.HasPrefix(logs[i], "Program log: ") {
+			return strings.TrimPrefix(logs[i], "Program log: ")
+		}
+	}
+
+	if len(logs) != 0 {
+		return logs[len(logs)-1]
+	}
+
+	return "no program logs"
+}
+
+func errorString(txErr interface{}) string {
+	raw, err := json.Marshal(txErr)
+	if err != nil {
+		return fmt.Sprint(txErr)
+	}
+
+	return string(raw)
+}
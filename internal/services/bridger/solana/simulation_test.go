@@ -0,0 +1,199 @@
+package solana
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+func TestTransactionError(t *testing.T) {
+	// the transaction errors are decoded from the RPC responses
+	decode := func(raw string) interface{} {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			t.Fatal(err)
+		}
+
+		return value
+	}
+
+	cases := []struct {
+		name  string
+		err   string
+		logs  []string
+		want  error
+		typed bool
+	}{
+		{
+			name:  "expired blockhash",
+			err:   `"BlockhashNotFound"`,
+			want:  errBlockhashExpired,
+			typed: true,
+		},
+		{
+			name: "transaction error",
+			err:  `"AccountInUse"`,
+		},
+		{
+			name: "compute budget instruction failure",
+			err:  `{"InstructionError":[0,"InvalidInstructionData"]}`,
+		},
+		{
+			name:  "compute budget exceeded",
+			err:   `{"InstructionError":[2,"ComputationalBudgetExceeded"]}`,
+			want:  bridge.ErrOutOfGas,
+			typed: true,
+		},
+		{
+			name:  "already withdrawn",
+			err:   `{"InstructionError":[2,{"Custom":0}]}`,
+			logs:  []string{"Program log: Allocate: account Address { address: 1 } already in use"},
+			want:  bridge.ErrAlreadyWithdrawn,
+			typed: true,
+		},
+		{
+			name:  "invalid signature",
+			err:   `{"InstructionError":[2,{"Custom":1}]}`,
+			logs:  []string{"Program log: Error: Wrong signature"},
+			want:  bridge.ErrInvalidSignature,
+			typed: true,
+		},
+		{
+			name:  "unknown program error",
+			err:   `{"InstructionError":[2,{"Custom":42}]}`,
+			logs:  []string{"Program log: something went wrong"},
+			want:  bridge.ErrWithdrawReverted,
+			typed: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := transactionError(decode(tc.err), tc.logs, 2)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			cause := errors.Cause(err)
+			if tc.typed && cause != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+			if !tc.typed && (bridge.IsPermanent(err) || bridge.IsTransient(err) || cause == errBlockhashExpired) {
+				t.Fatalf("expected an untyped error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProgramLog(t *testing.T) {
+	cases := []struct {
+		name string
+		logs []string
+		want string
+	}{
+		{name: "no logs", want: "no program logs"},
+		{
+			name: "last program log",
+			logs: []string{"Program log: first", "Program log: second", "Program X failed"},
+			want: "second",
+		},
+		{name: "no program log", logs: []string{"Program X failed"}, want: "Program X failed"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := programLog(tc.logs); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIncludedTxError(t *testing.T) {
+	// the transactions are decoded from the getTransaction responses with the withdrawal as the third instruction
+	confirmed := func(logs ...string) *confirmedTx {
+		raw, err := json.Marshal(map[string]interface{}{
+			"transaction": map[string]interface{}{
+				"message": map[string]interface{}{
+					"instructions": []interface{}{map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}},
+				},
+			},
+			"meta": map[string]interface{}{"fee": 5000, "logMessages": logs},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var tx *confirmedTx
+		if err = json.Unmarshal(raw, &tx); err != nil {
+			t.Fatal(err)
+		}
+
+		return tx
+	}
+	withdrawalErr := map[string]interface{}{"InstructionError": []interface{}{float64(2), map[string]interface{}{"Custom": float64(0)}}}
+
+	cases := []struct {
+		name  string
+		txErr interface{}
+		tx    *confirmedTx
+		want  error
+	}{
+		{
+			name:  "withdrawal account created by the other transaction",
+			txErr: withdrawalErr,
+			tx:    confirmed("Allocate: account Address { address: 9x } already in use"),
+			want:  bridge.ErrAlreadyWithdrawn,
+		},
+		{
+			name:  "invalid signature",
+			txErr: withdrawalErr,
+			tx:    confirmed("Program log: Error: wrong signature"),
+			want:  bridge.ErrInvalidSignature,
+		},
+		{
+			name:  "unknown withdrawal failure",
+			txErr: withdrawalErr,
+			tx:    confirmed("Program log: Error: something else"),
+			want:  bridge.ErrWithdrawReverted,
+		},
+		{
+			name:  "withdrawal out of compute units",
+			txErr: map[string]interface{}{"InstructionError": []interface{}{float64(2), "ComputationalBudgetExceeded"}},
+			tx:    confirmed(),
+			want:  bridge.ErrOutOfGas,
+		},
+		{
+			name:  "compute budget instruction failure",
+			txErr: map[string]interface{}{"InstructionError": []interface{}{float64(0), "InvalidInstructionData"}},
+			tx:    confirmed(),
+			want:  bridge.ErrTxFailed,
+		},
+		{
+			name:  "transaction level failure",
+			txErr: "InsufficientFundsForFee",
+			tx:    confirmed(),
+			want:  bridge.ErrTxFailed,
+		},
+		{
+			name:  "transaction meta is not available",
+			txErr: withdrawalErr,
+			want:  bridge.ErrTxFailed,
+		},
+		{
+			name:  "transaction meta is not available out of compute units",
+			txErr: map[string]interface{}{"InstructionError": []interface{}{float64(2), "ComputationalBudgetExceeded"}},
+			want:  bridge.ErrOutOfGas,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errors.Cause(includedTxError(tc.txErr, tc.tx)); got != tc.want {
+				t.Fatalf("got error %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
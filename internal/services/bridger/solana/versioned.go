@@ -0,0 +1,194 @@
+package solana
+
+import (
+	"github.com/olegfomenko/solana-go"
+	"github.com/pkg/errors"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+const (
+	// maxTransactionSize is the size of the serialized transaction accepted by the cluster, it is the IPv6 MTU
+	// without the headers
+	maxTransactionSize = 1232
+	// messageVersion0 is the prefix of the v0 message, the legacy messages start with the header instead
+	messageVersion0 = 0x80
+	maxAccountKeys  = 256
+)
+
+// lookupTable holds the activated addresses of the address lookup table
+type lookupTable struct {
+	address   solana.PublicKey
+	addresses []solana.PublicKey
+}
+
+type compiledAccount struct {
+	key      solana.PublicKey
+	signer   bool
+	writable bool
+	program  bool
+}
+
+// compileV0 compiles the instructions paid by the payer into the v0 message. The accounts found in the table
+// are loaded from it except for the signers and the invoked programs, which have to be listed in the message.
+func compileV0(
+	instructions []solana.Instruction,
+	payer solana.PublicKey,
+	blockhash solana.Hash,
+	table *lookupTable,
+) ([]byte, error) {
+	accounts := []*compiledAccount{{key: payer, signer: true, writable: true}}
+	byKey := map[solana.PublicKey]*compiledAccount{payer: accounts[0]}
+	add := func(key solana.PublicKey, signer, writable, program bool) {
+		account, ok := byKey[key]
+		if !ok {
+			account = &compiledAccount{key: key}
+			byKey[key] = account
+			accounts = append(accounts, account)
+		}
+
+		account.signer = account.signer || signer
+		account.writable = account.writable || writable
+		account.program = account.program || program
+	}
+
+	for _, instruction := range instructions {
+		add(instruction.ProgramID(), false, false, true)
+		for _, meta := range instruction.Accounts() {
+			add(meta.PublicKey, meta.IsSigner, meta.IsWritable, false)
+		}
+	}
+
+	tableIndexes := make(map[solana.PublicKey]int)
+	if table != nil {
+		for i, address := range table.addresses {
+			if _, ok := tableIndexes[address]; !ok {
+				tableIndexes[address] = i
+			}
+		}
+	}
+
+	// the static keys are ordered as the writable signers, the readonly signers, the writable and the readonly
+	// non-signers, the loaded ones follow them in the account index space
+	var writableSigners, readonlySigners, writable, readonly, loadedWritable, loadedReadonly []*compiledAccount
+	for _, account := range accounts {
+		if _, ok := tableIndexes[account.key]; ok && !account.signer && !account.program {
+			if account.writable {
+				loadedWritable = append(loadedWritable, account)
+			} else {
+				loadedReadonly = append(loadedReadonly, account)
+			}
+			continue
+		}
+
+		switch {
+		case account.signer && account.writable:
+			writableSigners = append(writableSigners, account)
+		case account.signer:
+			readonlySigners = append(readonlySigners, account)
+		case account.writable:
+			writable = append(writable, account)
+		default:
+			readonly = append(readonly, account)
+		}
+	}
+
+	static := concatAccounts(writableSigners, readonlySigners, writable, readonly)
+	ordered := concatAccounts(static, loadedWritable, loadedReadonly)
+	if len(ordered) > maxAccountKeys {
+		return nil, errors.Wrapf(bridge.ErrTxTooLarge, "transaction references %d accounts", len(ordered))
+	}
+
+	positions := make(map[solana.PublicKey]byte, len(ordered))
+	for i, account := range ordered {
+		positions[account.key] = byte(i)
+	}
+
+	message := []byte{
+		messageVersion0,
+		byte(len(writableSigners) + len(readonlySigners)),
+		byte(len(readonlySigners)),
+		byte(len(readonly)),
+	}
+
+	message = appendCompactU16(message, len(static))
+	for _, account := range static {
+		message = append(message, account.key[:]...)
+	}
+	message = append(message, blockhash[:]...)
+
+	message = appendCompactU16(message, len(instructions))
+	for _, instruction := range instructions {
+		data, err := instruction.Data()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode the instruction data")
+		}
+
+		message = append(message, positions[instruction.ProgramID()])
+		message = appendCompactU16(message, len(instruction.Accounts()))
+		for _, meta := range instruction.Accounts() {
+			message = append(message, positions[meta.PublicKey])
+		}
+		message = appendCompactU16(message, len(data))
+		message = append(message, data...)
+	}
+
+	if len(loadedWritable)+len(loadedReadonly) == 0 {
+		return appendCompactU16(message, 0), nil
+	}
+
+	message = appendCompactU16(message, 1)
+	message = append(message, table.address[:]...)
+	for _, loaded := range [][]*compiledAccount{loadedWritable, loadedReadonly} {
+		message = appendCompactU16(message, len(loaded))
+		for _, account := range loaded {
+			message = append(message, byte(tableIndexes[account.key]))
+		}
+	}
+
+	return message, nil
+}
+
+// encodeTransaction prepends the signatures to the message, the number of the signatures has to match the
+// number of the signers in the message header
+func encodeTransaction(message []byte, signatures ...solana.Signature) []byte {
+	tx := appendCompactU16(make([]byte, 0, 1+len(signatures)*len(solana.Signature{})+len(message)), len(signatures))
+	for _, signature := range signatures {
+		tx = append(tx, signature[:]...)
+	}
+
+	return append(tx, message...)
+}
+
+// checkSize makes sure the transaction of the message fits into the packet, so it is not rejected by the node
+func checkSize(message []byte) error {
+	// the header of the v0 message follows the version prefix
+	size := len(encodeTransaction(message, make([]solana.Signature, message[1])...))
+	if size > maxTransactionSize {
+		return errors.Wrapf(bridge.ErrTxTooLarge, "transaction of %d bytes exceeds the limit of %d bytes",
+			size, maxTransactionSize)
+	}
+
+	return nil
+}
+
+func concatAccounts(groups ...[]*compiledAccount) []*compiledAccount {
+	result := make([]*compiledAccount, 0)
+	for _, group := range groups {
+		result = append(result, group...)
+	}
+
+	return result
+}
+
+// appendCompactU16 appends the length in the compact-u16 encoding of the Solana wire format
+func appendCompactU16(buf []byte, value int) []byte {
+	for {
+		part := byte(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			return append(buf, part)
+		}
+
+		buf = append(buf, part|0x80)
+	}
+}
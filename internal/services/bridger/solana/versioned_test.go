@@ -0,0 +1,202 @@
+package solana
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/olegfomenko/solana-go"
+	"github.com/pkg/errors"
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+)
+
+func TestAppendCompactU16(t *testing.T) {
+	cases := []struct {
+		value int
+		want  []byte
+	}{
+		{value: 0, want: []byte{0x00}},
+		{value: 127, want: []byte{0x7f}},
+		{value: 128, want: []byte{0x80, 0x01}},
+		{value: 255, want: []byte{0xff, 0x01}},
+		{value: 16383, want: []byte{0xff, 0x7f}},
+		{value: 16384, want: []byte{0x80, 0x80, 0x01}},
+	}
+
+	for _, tc := range cases {
+		if got := appendCompactU16(nil, tc.value); !bytes.Equal(got, tc.want) {
+			t.Errorf("appendCompactU16(%d) = %x, want %x", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestCompileV0(t *testing.T) {
+	key := func(b byte) solana.PublicKey {
+		var k solana.PublicKey
+		k[0] = b
+		return k
+	}
+
+	payer, program, writable, readonly, other, tableAddress := key(1), key(2), key(3), key(4), key(5), key(6)
+	blockhash := solana.Hash{7}
+	data := []byte{0xaa, 0xbb}
+	instruction := solana.NewInstruction(program, solana.AccountMetaSlice{
+		solana.Meta(writable).WRITE(),
+		solana.Meta(readonly),
+		solana.Meta(payer).WRITE().SIGNER(),
+	}, data)
+
+	message := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+
+	cases := []struct {
+		name  string
+		table *lookupTable
+		want  []byte
+	}{
+		{
+			name: "static accounts",
+			want: message(
+				[]byte{messageVersion0, 1, 0, 2},
+				[]byte{4}, payer[:], writable[:], program[:], readonly[:],
+				blockhash[:],
+				[]byte{1, 2, 3, 1, 3, 0, 2}, data,
+				[]byte{0},
+			),
+		},
+		{
+			name:  "accounts missing in the table",
+			table: &lookupTable{address: tableAddress, addresses: []solana.PublicKey{other}},
+			want: message(
+				[]byte{messageVersion0, 1, 0, 2},
+				[]byte{4}, payer[:], writable[:], program[:], readonly[:],
+				blockhash[:],
+				[]byte{1, 2, 3, 1, 3, 0, 2}, data,
+				[]byte{0},
+			),
+		},
+		{
+			name: "loaded accounts",
+			// the payer and the program are listed in the message even though the table has them
+			table: &lookupTable{address: tableAddress, addresses: []solana.PublicKey{other, readonly, writable, payer, program}},
+			want: message(
+				[]byte{messageVersion0, 1, 0, 1},
+				[]byte{2}, payer[:], program[:],
+				blockhash[:],
+				[]byte{1, 1, 3, 2, 3, 0, 2}, data,
+				[]byte{1}, tableAddress[:], []byte{1, 2}, []byte{1, 1},
+			),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := compileV0([]solana.Instruction{instruction}, payer, blockhash, tc.table)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Fatalf("unexpected message\n got: %x\nwant: %x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	// the single signature and its length take 65 bytes of the transaction
+	cases := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{name: "fits", size: maxTransactionSize - 65},
+		{name: "too large", size: maxTransactionSize - 64, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			message := make([]byte, tc.size)
+			message[0], message[1] = messageVersion0, 1
+
+			err := checkSize(message)
+			if tc.wantErr && errors.Cause(err) != bridge.ErrTxTooLarge {
+				t.Fatalf("expected %v, got %v", bridge.ErrTxTooLarge, err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckFits(t *testing.T) {
+	key := func(b byte) solana.PublicKey {
+		var k solana.PublicKey
+		k[0], k[1] = b, 1
+		return k
+	}
+
+	payer, program := key(0), key(1)
+	withAccounts := func(count, dataSize int) (solana.Instruction, []solana.PublicKey) {
+		metas := make(solana.AccountMetaSlice, 0, count)
+		shared := make([]solana.PublicKey, 0, count)
+		for i := 0; i < count; i++ {
+			metas = append(metas, solana.Meta(key(byte(i+2))))
+			shared = append(shared, key(byte(i+2)))
+		}
+
+		return solana.NewInstruction(program, metas, make([]byte, dataSize)), shared
+	}
+
+	cases := []struct {
+		name     string
+		managed  bool
+		accounts int
+		dataSize int
+		table    func(shared []solana.PublicKey) *lookupTable
+		want     error
+	}{
+		{name: "fits without table", managed: true, accounts: 5, dataSize: 100},
+		{name: "too large without table", accounts: 35, dataSize: 100, want: bridge.ErrTxTooLarge},
+		{name: "managed table is created", managed: true, accounts: 35, dataSize: 100, want: bridge.ErrTxNotReady},
+		{
+			name:     "managed table is extended",
+			managed:  true,
+			accounts: 35,
+			dataSize: 100,
+			table: func(shared []solana.PublicKey) *lookupTable {
+				return &lookupTable{address: key(200), addresses: shared[:5]}
+			},
+			want: bridge.ErrTxNotReady,
+		},
+		{
+			name:     "managed table holds the accounts",
+			managed:  true,
+			accounts: 35,
+			dataSize: 100,
+			table: func(shared []solana.PublicKey) *lookupTable {
+				return &lookupTable{address: key(200), addresses: shared}
+			},
+		},
+		{name: "too large with table", managed: true, accounts: 35, dataSize: 1100, want: bridge.ErrTxTooLarge},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &solanaBridger{solana: &config.Solana{}}
+			b.solana.LookupTable.Managed = tc.managed
+
+			instruction, shared := withAccounts(tc.accounts, tc.dataSize)
+			var table *lookupTable
+			if tc.table != nil {
+				table = tc.table(shared)
+			}
+
+			err := b.checkFits([]solana.Instruction{instruction}, payer, table, shared)
+			if errors.Cause(err) != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/failover"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+// endpointPool is the failover pool of any client type
+type endpointPool interface {
+	Check(ctx context.Context, log *logan.Entry)
+	Available() bool
+}
+
+type endpointChecker struct {
+	log   *logan.Entry
+	cfg   config.Config
+	redis redis.Rediser
+}
+
+// RunEndpointChecker checks the health of the chain endpoints, so the failed ones are dialed again
+// in background and the chains without any healthy endpoint are marked unavailable
+func RunEndpointChecker(cfg config.Config, ctx context.Context) {
+	log := cfg.Log().WithField("service", "endpoint_checker")
+	c := endpointChecker{
+		log:   log,
+		cfg:   cfg,
+		redis: cfg.Redis(),
+	}
+
+	interval := failover.DefaultCheckInterval
+	running.WithBackOff(ctx, log, "run_once", c.runOnce, interval, interval, 10*interval)
+}
+
+func (c *endpointChecker) runOnce(ctx context.Context) error {
+	chainPools := c.chainPools()
+
+	var wg sync.WaitGroup
+	for _, pools := range chainPools {
+		for _, pool := range pools {
+			wg.Add(1)
+			go func(pool endpointPool) {
+				defer wg.Done()
+				pool.Check(ctx, c.log)
+			}(pool)
+		}
+	}
+	wg.Wait()
+
+	for chain, pools := range chainPools {
+		available := true
+		for _, pool := range pools {
+			available = available && pool.Available()
+		}
+
+		metrics.ChainAvailable.WithLabelValues(chain).Set(metrics.BoolToFloat(available))
+		if !available {
+			c.log.WithField("chain", chain).Warn("chain has no healthy endpoints")
+		}
+
+		if err := c.redis.SetChainAvailable(ctx, chain, available); err != nil {
+			return errors.Wrap(err, "failed to store the chain availability", logan.F{"chain": chain})
+		}
+	}
+
+	return nil
+}
+
+// chainPools maps the chains to their pools, it is resolved on every run as the chain families
+// may be discovered from the core params after the start
+func (c *endpointChecker) chainPools() map[string][]endpointPool {
+	pools := make(map[string][]endpointPool)
+	for _, chain := range c.cfg.Chains().Names() {
+		family, _ := c.cfg.Chains().Family(chain)
+		switch family {
+		case types.FamilyEVM:
+			if evmChain, ok := c.cfg.EVM().GetChainByName(chain); ok {
+				pools[chain] = []endpointPool{evmChain.RPC}
+			}
+		case types.FamilySolana:
+			pools[chain] = []endpointPool{c.cfg.Solana().RPC}
+		case types.FamilyNear:
+			pools[chain] = []endpointPool{c.cfg.Near().RPC}
+		}
+	}
+
+	return pools
+}
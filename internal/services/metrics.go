@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rarimo/relayer-svc/internal/config"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// RunMetrics serves the prometheus metrics until ctx is done
+func RunMetrics(cfg config.Config, ctx context.Context) {
+	log := cfg.Log().WithField("service", "metrics")
+	addr := cfg.Metrics().Addr
+	if addr == "" {
+		log.Info("metrics server is disabled")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.WithError(err).Error("failed to shutdown the metrics server")
+		}
+	}()
+
+	log.WithFields(logan.F{"addr": addr}).Info("serving metrics")
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		panic(err)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+type networksWatcher struct {
+	log      *logan.Entry
+	cfg      config.Config
+	networks *config.Networks
+}
+
+// RunNetworksWatcher keeps the chain params discovered from the core up to date and reports
+// the local config values that disagree with them
+func RunNetworksWatcher(cfg config.Config, ctx context.Context) {
+	log := cfg.Log().WithField("service", "networks_watcher")
+	if cfg.Networks().Disabled {
+		log.Info("networks discovery is disabled")
+		return
+	}
+
+	w := networksWatcher{
+		log:      log,
+		cfg:      cfg,
+		networks: cfg.Networks(),
+	}
+
+	interval := w.networks.RefreshInterval
+	running.WithBackOff(ctx, log, "run_once", w.runOnce, interval, interval, 10*interval)
+}
+
+func (w *networksWatcher) runOnce(ctx context.Context) error {
+	changed, err := w.networks.Refresh(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to refresh the networks")
+	}
+	if len(changed) > 0 {
+		w.log.WithField("networks", strings.Join(changed, ",")).Info("core network params changed")
+	}
+
+	for _, chain := range w.cfg.Chains().Names() {
+		w.checkChain(chain)
+	}
+
+	return nil
+}
+
+// checkChain compares the configured chain params with the discovered ones
+func (w *networksWatcher) checkChain(chain string) {
+	log := w.log.WithField("chain", chain)
+
+	network, ok := w.networks.Get(chain)
+	if !ok {
+		log.Warn("chain is not registered in the core params")
+		return
+	}
+
+	family, ok := network.Family()
+	if !ok {
+		log.WithField("type", network.Type.String()).Warn("chain has the unsupported network type in the core params")
+	}
+
+	declared := w.cfg.Chains().DeclaredFamily(chain)
+	w.report(log, chain, "family", string(declared), string(family))
+
+	switch declared {
+	case types.FamilyEVM:
+		evmChain, ok := w.cfg.EVM().GetChainByName(chain)
+		if !ok || evmChain.BridgeAddress == (common.Address{}) {
+			return
+		}
+		w.report(log, chain, "bridge_address", strings.ToLower(evmChain.BridgeAddress.Hex()), strings.ToLower(network.Contract))
+	case types.FamilySolana:
+		cfg := w.cfg.Solana()
+		if cfg.BridgeProgramID.IsZero() {
+			return
+		}
+		w.report(log, chain, "bridge_program_id", cfg.BridgeProgramID.String(), network.Contract)
+		if network.Admin != "" {
+			w.report(log, chain, "bridge_admin", cfg.BridgeAdmin.String(), network.Admin)
+		}
+	case types.FamilyNear:
+		cfg := w.cfg.Near()
+		if cfg.BridgeAddress == "" {
+			return
+		}
+		w.report(log, chain, "bridge_address", cfg.BridgeAddress, network.Contract)
+	}
+}
+
+// report alerts when the configured value is set and differs from the discovered one
+func (w *networksWatcher) report(log *logan.Entry, chain, field, configured, discovered string) {
+	mismatch := configured != "" && discovered != "" && configured != discovered
+	metrics.ConfigMismatch.WithLabelValues(chain, field).Set(metrics.BoolToFloat(mismatch))
+	if !mismatch {
+		return
+	}
+
+	log.WithFields(logan.F{
+		"field":      field,
+		"configured": configured,
+		"discovered": discovered,
+	}).Error("local config disagrees with the core params")
+}
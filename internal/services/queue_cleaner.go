@@ -12,15 +12,17 @@ import (
 )
 
 type queueCleaner struct {
-	log   *logan.Entry
-	redis redis.Rediser
+	log    *logan.Entry
+	redis  redis.Rediser
+	chains *config.Chains
 }
 
 func RunQueueCleaner(cfg config.Config, ctx context.Context) {
 	log := cfg.Log().WithField("service", "queue_cleaner")
 	q := queueCleaner{
-		log:   log,
-		redis: cfg.Redis(),
+		log:    log,
+		redis:  cfg.Redis(),
+		chains: cfg.Chains(),
 	}
 
 	running.WithBackOff(ctx, log, "run_once", q.runOnce, 10*time.Minute, 10*time.Second, time.Minute)
@@ -32,14 +34,21 @@ func (q *queueCleaner) runOnce(ctx context.Context) error {
 		return errors.Wrap(err, "failed to clean the redis queue")
 	}
 
-	ready, err := q.redis.OpenRelayQueue().PurgeReady()
-	if err != nil {
-		return errors.Wrap(err, "failed to clean the ready tasks")
-	}
-
-	rejected, err := q.redis.OpenRelayQueue().PurgeRejected()
-	if err != nil {
-		return errors.Wrap(err, "failed to clean the rejected tasks")
+	// the queues of the chains served by the other instances are cleaned by them
+	var ready, rejected int64
+	for _, chain := range append([]string{""}, q.chains.Names()...) {
+		queue := q.redis.OpenRelayQueue(chain)
+
+		purged, err := queue.PurgeReady()
+		if err != nil {
+			return errors.Wrap(err, "failed to clean the ready tasks", logan.F{"chain": chain})
+		}
+		ready += purged
+
+		if purged, err = queue.PurgeRejected(); err != nil {
+			return errors.Wrap(err, "failed to clean the rejected tasks", logan.F{"chain": chain})
+		}
+		rejected += purged
 	}
 	q.log.Infof("Cleaned %d stuck, %d ready, %d rejected jobs", stuck, ready, rejected)
 
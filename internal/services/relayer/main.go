@@ -13,11 +13,17 @@ import (
 	tokenmanager "github.com/rarimo/rarimo-core/x/tokenmanager/types"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
 
 	"github.com/rarimo/relayer-svc/internal/config"
 	"github.com/rarimo/relayer-svc/internal/data"
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/metrics"
 	"github.com/rarimo/relayer-svc/internal/services/bridger"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+	"github.com/rarimo/relayer-svc/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -26,51 +32,84 @@ const (
 	prefetchLimit = 10
 	pollDuration  = 100 * time.Millisecond
 	numConsumers  = 100
+
+	unparkPeriod = 10 * time.Second
 )
 
+// ErrChainPaused is returned when relaying to the destination chain is paused, such tasks are parked until it is resumed
+var ErrChainPaused = errors.New("chain is paused")
+
 type relayer struct {
-	log   *logan.Entry
-	queue rmq.Queue
+	log    *logan.Entry
+	queues []rmq.Queue
+	redis  redis.Rediser
+	// known tracks the chains seen paused or parked to report their resumed state as well
+	known map[string]struct{}
 }
 
 type relayerConsumer struct {
+	// ctx is the relayer run context, so the stuck withdrawals are interrupted on the shutdown
+	ctx             context.Context
 	log             *logan.Entry
 	rarimocore      rarimocore.QueryClient
 	tokenmanager    tokenmanager.QueryClient
 	bridgerProvider bridger.BridgerProvider
-	queue           rmq.Queue
+	redis           redis.Rediser
+	// shared tells the consumer of the queue shared by all the chains, its tasks to the chains served by the
+	// other relayer instances are forwarded to their queues
+	shared  bool
+	chains  *config.Chains
+	signers *signerpool.Pool
 }
 
 func Run(cfg config.Config, ctx context.Context) {
 	log := cfg.Log().WithField("service", "relayer")
 	r := relayer{
 		log:   log,
-		queue: cfg.Redis().OpenRelayQueue(),
+		redis: cfg.Redis(),
+		known: make(map[string]struct{}),
 	}
 
-	if err := r.queue.StartConsuming(prefetchLimit, pollDuration); err != nil {
-		panic(errors.Wrap(err, "failed to start consuming the relay queue"))
-	}
+	// the instance consumes the queues of the chains it is configured for and the shared one
+	for _, chain := range append([]string{""}, cfg.Chains().Names()...) {
+		queue := cfg.Redis().OpenRelayQueue(chain)
+		if err := queue.StartConsuming(prefetchLimit, pollDuration); err != nil {
+			panic(errors.Wrap(err, "failed to start consuming the relay queue", logan.F{"chain": chain}))
+		}
+
+		for i := 0; i < numConsumers; i++ {
+			name := fmt.Sprintf("relay-consumer-%d", i)
+			if chain != "" {
+				name = fmt.Sprintf("relay-consumer-%s-%d", chain, i)
+			}
 
-	for i := 0; i < numConsumers; i++ {
-		name := fmt.Sprintf("relay-consumer-%d", i)
-		if _, err := r.queue.AddConsumer(name, newConsumer(cfg, name)); err != nil {
-			panic(err)
+			if _, err := queue.AddConsumer(name, newConsumer(ctx, cfg, name, chain == "")); err != nil {
+				panic(err)
+			}
 		}
+
+		r.queues = append(r.queues, queue)
 	}
 
-	<-ctx.Done()
-	<-r.queue.StopConsuming()
-	r.log.Info("finished consuming relayer queue")
+	running.WithBackOff(ctx, r.log, "unparker", r.unparkTasks, unparkPeriod, unparkPeriod, time.Minute)
+
+	for _, queue := range r.queues {
+		<-queue.StopConsuming()
+	}
+	r.log.Info("finished consuming relayer queues")
 }
 
-func newConsumer(cfg config.Config, id string) *relayerConsumer {
+func newConsumer(ctx context.Context, cfg config.Config, id string, shared bool) *relayerConsumer {
 	return &relayerConsumer{
+		ctx:             ctx,
 		log:             cfg.Log().WithField("service", id),
 		rarimocore:      rarimocore.NewQueryClient(cfg.Cosmos()),
 		tokenmanager:    tokenmanager.NewQueryClient(cfg.Cosmos()),
-		queue:           cfg.Redis().OpenRelayQueue(),
+		redis:           cfg.Redis(),
+		chains:          cfg.Chains(),
 		bridgerProvider: bridger.NewBridgerProvider(cfg),
+		signers:         cfg.SignerPool(),
+		shared:          shared,
 	}
 }
 
@@ -84,10 +123,32 @@ func (c *relayerConsumer) Consume(delivery rmq.Delivery) {
 	var task data.RelayTask
 	task.Unmarshal(delivery.Payload())
 
-	if err := c.processTransfer(context.TODO(), task); err != nil {
-		if errors.Cause(err) == bridge.ErrAlreadyWithdrawn {
+	if err := c.processTransfer(c.ctx, &task); err != nil {
+		switch cause := errors.Cause(err); {
+		case cause == bridge.ErrAlreadyWithdrawn:
 			c.log.WithField("transfer_id", task.OperationIndex).Info("transfer was already withdrawn")
 			return
+		case cause == bridge.ErrUnknownChain && c.shared && c.chains.IsRemote(task.ToChain):
+			c.log.WithFields(logan.F{
+				"transfer_id": task.OperationIndex,
+				"to_chain":    task.ToChain,
+			}).Info("chain is served by the other instance, forwarding the transfer to its queue")
+			c.mustForward(delivery, task)
+			return
+		case cause == ErrChainPaused, bridge.IsTransient(err):
+			c.log.WithError(err).WithFields(logan.F{
+				"transfer_id": task.OperationIndex,
+				"to_chain":    task.ToChain,
+			}).Info("chain is not available, parking the transfer")
+			c.mustPark(delivery, task)
+			return
+		case bridge.IsPermanent(err):
+			c.log.WithError(err).WithFields(logan.F{
+				"transfer_id": task.OperationIndex,
+				"to_chain":    task.ToChain,
+			}).Error("transfer can never succeed, dead-lettering it")
+			c.mustDeadLetter(delivery, task, err)
+			return
 		}
 
 		c.log.WithError(err).WithField("transfer_id", task.OperationIndex).Error("failed to process transfer")
@@ -101,9 +162,22 @@ func (c *relayerConsumer) Consume(delivery rmq.Delivery) {
 	}
 }
 
-func (c *relayerConsumer) processTransfer(ctx context.Context, task data.RelayTask) error {
+func (c *relayerConsumer) processTransfer(ctx context.Context, task *data.RelayTask) (err error) {
+	ctx, span := tracing.Start(
+		tracing.Extract(ctx, task.TraceContext),
+		"relayer.processTransfer",
+		attribute.String("op_id", task.OperationIndex),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	log := c.log.WithField("op_id", task.OperationIndex)
 
+	if task.ToChain != "" {
+		if err := c.ensureRelayable(ctx, task.ToChain); err != nil {
+			return err
+		}
+	}
+
 	log.Info("processing a transfer")
 	operation, err := c.rarimocore.Operation(ctx, &rarimocore.QueryGetOperationRequest{Index: task.OperationIndex})
 	if err != nil {
@@ -116,6 +190,12 @@ func (c *relayerConsumer) processTransfer(ctx context.Context, task data.RelayTa
 	if err := transfer.Unmarshal(operation.Operation.Details.Value); err != nil {
 		return errors.Wrap(err, "failed to unmarshal  transfer")
 	}
+	if task.ToChain == "" {
+		task.ToChain = transfer.To.Chain
+		if err := c.ensureRelayable(ctx, task.ToChain); err != nil {
+			return err
+		}
+	}
 
 	tokenDetails, err := c.tokenmanager.ItemByOnChainItem(ctx, &tokenmanager.QueryGetItemByOnChainItemRequest{
 		Address: transfer.To.Address,
@@ -149,6 +229,7 @@ func (c *relayerConsumer) processTransfer(ctx context.Context, task data.RelayTa
 		Signature:      task.Signature,
 		Origin:         task.Origin,
 		MerklePath:     task.MustParseMerklePath(),
+		Resubmissions:  task.Resubmissions,
 	}
 
 	f := logan.F{
@@ -158,8 +239,132 @@ func (c *relayerConsumer) processTransfer(ctx context.Context, task data.RelayTa
 	}
 
 	log.WithFields(f).Info("relaying a transfer")
+	span.SetAttributes(attribute.String("to_chain", transfer.To.Chain))
+
+	bridger, err := c.bridgerProvider.GetBridger(transfer.To.Chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the bridger")
+	}
+
+	submission, err := bridger.Submit(ctx, transferDetails)
+	if err != nil {
+		return err
+	}
+
+	// the consumer does not wait for the transaction to be included, the tracker confirms it in background
+	if err = addPendingTask(ctx, c.redis, *task, *submission); err != nil {
+		return errors.Wrap(err, "failed to store the submitted transfer")
+	}
+	if err = c.signers.Acquired(ctx, submission.Chain, submission.Signer); err != nil {
+		log.WithError(err).WithField("signer", submission.Signer).Warn("failed to count the signer load")
+	}
+
+	return nil
+}
+
+// ensureRelayable checks that the chain is configured and is neither paused nor unavailable, so the
+// task is parked without waiting for the chain RPC to fail
+func (c *relayerConsumer) ensureRelayable(ctx context.Context, chain string) error {
+	if !c.chains.Has(chain) {
+		return errors.From(bridge.ErrUnknownChain, logan.F{"chain": chain})
+	}
+
+	_, paused, err := c.redis.ChainPauseReason(ctx, chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the chain is paused")
+	}
+	if paused {
+		return errors.From(ErrChainPaused, logan.F{"chain": chain})
+	}
+
+	unavailable, err := c.redis.UnavailableChains(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the chain is available")
+	}
+	if _, ok := unavailable[chain]; ok {
+		return errors.From(bridge.ErrChainUnavailable, logan.F{"chain": chain})
+	}
+
+	return nil
+}
+
+func (c *relayerConsumer) mustPark(delivery rmq.Delivery, task data.RelayTask) {
+	if err := c.redis.ParkTask(context.TODO(), task.ToChain, task.Marshal()); err != nil {
+		panic(errors.Wrap(err, "failed to park the task"))
+	}
+
+	if err := delivery.Ack(); err != nil {
+		panic(errors.Wrap(err, fmt.Sprintf("failed to ack the parked transfer %s", task.OperationIndex)))
+	}
+}
+
+// mustForward moves the task of the shared queue to the queue of its chain
+func (c *relayerConsumer) mustForward(delivery rmq.Delivery, task data.RelayTask) {
+	if err := c.redis.OpenRelayQueue(task.ToChain).PublishBytes(task.Marshal()); err != nil {
+		panic(errors.Wrap(err, "failed to forward the task"))
+	}
+
+	if err := delivery.Ack(); err != nil {
+		panic(errors.Wrap(err, fmt.Sprintf("failed to ack the forwarded transfer %s", task.OperationIndex)))
+	}
+}
+
+func (c *relayerConsumer) mustDeadLetter(delivery rmq.Delivery, task data.RelayTask, reason error) {
+	if err := c.redis.DeadLetterTask(context.TODO(), task.Marshal(), reason.Error()); err != nil {
+		panic(errors.Wrap(err, "failed to dead-letter the task"))
+	}
+	metrics.DeadLetteredTasks.WithLabelValues(task.ToChain).Inc()
+
+	if err := delivery.Ack(); err != nil {
+		panic(errors.Wrap(err, fmt.Sprintf("failed to ack the dead-lettered transfer %s", task.OperationIndex)))
+	}
+}
+
+func (r *relayer) unparkTasks(ctx context.Context) error {
+	paused, err := r.redis.PausedChains(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get paused chains")
+	}
+
+	parked, err := r.redis.ParkedChains(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get chains with parked tasks")
+	}
+
+	unavailable, err := r.redis.UnavailableChains(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get unavailable chains")
+	}
+
+	for chain := range paused {
+		r.known[chain] = struct{}{}
+	}
+	for _, chain := range parked {
+		r.known[chain] = struct{}{}
+	}
+	for chain := range r.known {
+		_, isPaused := paused[chain]
+		metrics.ChainPaused.WithLabelValues(chain).Set(metrics.BoolToFloat(isPaused))
+	}
+
+	for _, chain := range parked {
+		if _, isPaused := paused[chain]; isPaused {
+			continue
+		}
+		if _, isUnavailable := unavailable[chain]; isUnavailable {
+			continue
+		}
+
+		count, err := r.redis.UnparkTasks(ctx, chain)
+		if err != nil {
+			return errors.Wrap(err, "failed to unpark tasks", logan.F{"chain": chain})
+		}
+		if count > 0 {
+			r.log.WithFields(logan.F{"chain": chain, "count": count}).Info("returned parked tasks to the relay queue")
+		}
+	}
 
-	return c.bridgerProvider.GetBridger(transfer.To.Chain).Withdraw(ctx, transferDetails)
+	return nil
 }
 
 func mustReject(delivery rmq.Delivery) {
@@ -180,7 +385,7 @@ func (c *relayerConsumer) mustScheduleRetry(task data.RelayTask) {
 	}
 
 	task.RetriesLeft--
-	if err := c.queue.PublishBytes(task.Marshal()); err != nil {
+	if err := c.redis.OpenRelayQueue(task.ToChain).PublishBytes(task.Marshal()); err != nil {
 		panic(errors.Wrap(err, "failed to schedule the retry"))
 	}
 
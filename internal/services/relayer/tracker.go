@@ -0,0 +1,245 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/services/bridger"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
+	"github.com/rarimo/relayer-svc/internal/signerpool"
+)
+
+// pendingTask is the relay task with the withdrawal submitted to the destination chain
+type pendingTask struct {
+	Task       data.RelayTask    `json:"task"`
+	Submission bridge.Submission `json:"submission"`
+}
+
+func addPendingTask(ctx context.Context, rediser redis.Rediser, task data.RelayTask, submission bridge.Submission) error {
+	raw, err := json.Marshal(pendingTask{Task: task, Submission: submission})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the pending task")
+	}
+
+	return rediser.AddPendingTask(ctx, submission.Origin, raw)
+}
+
+type tracker struct {
+	log      *logan.Entry
+	cfg      *config.TrackerConfig
+	redis    redis.Rediser
+	chains   *config.Chains
+	bridgers bridger.BridgerProvider
+	signers  *signerpool.Pool
+}
+
+// RunTracker confirms the submitted withdrawals, finalizing the tasks with the included transactions and
+// returning the ones with the dropped or failed transactions to the relay queue
+func RunTracker(cfg config.Config, ctx context.Context) {
+	log := cfg.Log().WithField("service", "tracker")
+	t := tracker{
+		log:      log,
+		cfg:      cfg.Tracker(),
+		redis:    cfg.Redis(),
+		chains:   cfg.Chains(),
+		bridgers: bridger.NewBridgerProvider(cfg),
+		signers:  cfg.SignerPool(),
+	}
+
+	interval := t.cfg.Interval
+	running.WithBackOff(ctx, log, "run_once", t.runOnce, interval, interval, 10*interval)
+}
+
+func (t *tracker) runOnce(ctx context.Context) error {
+	tasks, err := t.redis.PendingTasks(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pending tasks")
+	}
+
+	workers := make(chan struct{}, t.cfg.Workers)
+	var wg sync.WaitGroup
+	for origin, raw := range tasks {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(origin, raw string) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			if err := t.track(ctx, raw); err != nil {
+				t.log.WithError(err).WithField("op_id", origin).Error("failed to track the pending task")
+			}
+		}(origin, raw)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (t *tracker) track(ctx context.Context, raw string) error {
+	var pending pendingTask
+	if err := json.Unmarshal([]byte(raw), &pending); err != nil {
+		return errors.Wrap(err, "failed to unmarshal the pending task")
+	}
+
+	submission := pending.Submission
+	if !t.chains.Has(submission.Chain) {
+		// the withdrawal is confirmed by the instance serving the chain
+		return nil
+	}
+
+	log := t.log.WithFields(logan.F{
+		"op_id":    submission.Origin,
+		"to_chain": submission.Chain,
+		"tx_id":    submission.TxHash,
+	})
+
+	bridger, err := t.bridgers.GetBridger(submission.Chain)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the bridger")
+	}
+
+	result, err := bridger.Confirm(ctx, &submission)
+	switch cause := errors.Cause(err); {
+	case err == nil, cause == bridge.ErrAlreadyWithdrawn:
+		return t.finalize(ctx, pending, submission, result)
+	case cause == bridge.ErrOutOfGas:
+		if pending.Task.Resubmissions >= t.cfg.MaxResubmissions {
+			log.WithError(err).Error("transactions keep running out of gas, dead-lettering the transfer")
+			return t.deadLetter(ctx, pending.Task, submission, err)
+		}
+
+		log.WithError(err).Warn("transaction ran out of gas, submitting the transfer again")
+		return t.requeue(ctx, pending.Task, submission)
+	case cause == bridge.ErrPending, bridge.IsTransient(err):
+		if err != nil && cause != bridge.ErrPending {
+			log.WithError(err).Warn("transaction is not confirmed yet")
+		}
+		if time.Since(pending.Submission.SubmittedAt) > t.cfg.MaxPending {
+			log.Warn("transaction is pending for too long, submitting the transfer again")
+			return t.requeue(ctx, pending.Task, submission)
+		}
+		if submission != pending.Submission {
+			return addPendingTask(ctx, t.redis, pending.Task, submission)
+		}
+
+		return nil
+	case cause == bridge.ErrTxDropped:
+		log.WithError(err).Warn("transaction was dropped, submitting the transfer again")
+		return t.requeue(ctx, pending.Task, submission)
+	case cause == bridge.ErrTxFailed && pending.Task.RetriesLeft > 0:
+		log.WithError(err).Error("transaction failed, retrying the transfer")
+		pending.Task.RetriesLeft--
+		return t.requeue(ctx, pending.Task, submission)
+	case cause == bridge.ErrTxFailed, bridge.IsPermanent(err):
+		log.WithError(err).Error("transfer can never succeed, dead-lettering it")
+		return t.deadLetter(ctx, pending.Task, submission, err)
+	default:
+		// the confirmation is checked again on the next run, as the failure may be caused by the node
+		if time.Since(pending.Submission.SubmittedAt) > t.cfg.MaxPending {
+			log.WithError(err).Warn("failed to confirm the transaction for too long, submitting the transfer again")
+			return t.requeue(ctx, pending.Task, submission)
+		}
+
+		return errors.Wrap(err, "failed to confirm the transaction")
+	}
+}
+
+// finalize completes the task, the result is nil when the transfer was withdrawn by someone else
+func (t *tracker) finalize(ctx context.Context, pending pendingTask, submission bridge.Submission, result *bridge.Result) error {
+	removed, err := t.remove(ctx, submission)
+	if err != nil || !removed {
+		return err
+	}
+
+	err = t.redis.RecordTaskEvent(ctx, submission.Origin, redis.TaskEvent{
+		Event:  redis.TaskEventConfirmed,
+		Chain:  submission.Chain,
+		TxHash: submission.TxHash,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to record the confirmation")
+	}
+
+	fields := logan.F{
+		"transfer_id": pending.Task.OperationIndex,
+		"to_chain":    submission.Chain,
+		"tx_id":       submission.TxHash,
+	}
+
+	if result != nil {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the withdrawal result")
+		}
+		if err = t.redis.StoreTaskResult(ctx, submission.Origin, raw); err != nil {
+			return errors.Wrap(err, "failed to store the withdrawal result")
+		}
+
+		fields = fields.Merge(logan.F{
+			"block":           result.Block,
+			"gas_used":        result.GasUsed,
+			"effective_price": result.EffectivePrice,
+			"fee":             result.Fee,
+			"signer":          result.Signer,
+		})
+	}
+
+	t.log.WithFields(fields).Info("transfer relayed")
+
+	return nil
+}
+
+// requeue publishes the task to the relay queue again unless the other replica has done it already
+func (t *tracker) requeue(ctx context.Context, task data.RelayTask, submission bridge.Submission) error {
+	removed, err := t.remove(ctx, submission)
+	if err != nil || !removed {
+		return err
+	}
+
+	task.Resubmissions++
+	if err = t.redis.OpenRelayQueue(task.ToChain).PublishBytes(task.Marshal()); err != nil {
+		return errors.Wrap(err, "failed to publish the task")
+	}
+
+	return nil
+}
+
+func (t *tracker) deadLetter(ctx context.Context, task data.RelayTask, submission bridge.Submission, reason error) error {
+	removed, err := t.remove(ctx, submission)
+	if err != nil || !removed {
+		return err
+	}
+
+	if err = t.redis.DeadLetterTask(ctx, task.Marshal(), reason.Error()); err != nil {
+		return errors.Wrap(err, "failed to dead-letter the task")
+	}
+	metrics.DeadLetteredTasks.WithLabelValues(task.ToChain).Inc()
+
+	return nil
+}
+
+// remove stops tracking the submission, returning false if the other replica has done it already
+func (t *tracker) remove(ctx context.Context, submission bridge.Submission) (bool, error) {
+	removed, err := t.redis.RemovePendingTask(ctx, submission.Origin)
+	if err != nil || !removed {
+		return false, err
+	}
+
+	if err = t.signers.Released(ctx, submission.Chain, submission.Signer); err != nil {
+		t.log.WithError(err).WithField("signer", submission.Signer).Warn("failed to release the signer load")
+	}
+
+	return true, nil
+}
@@ -14,13 +14,17 @@ import (
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 	"gitlab.com/distributed_lab/running"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/slices"
 
 	rarimocore "github.com/rarimo/rarimo-core/x/rarimocore/types"
 	"github.com/rarimo/relayer-svc/internal/config"
 	"github.com/rarimo/relayer-svc/internal/data"
 	"github.com/rarimo/relayer-svc/internal/data/core"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/internal/services/bridger/bridge"
 	"github.com/rarimo/relayer-svc/internal/services/relayer"
+	"github.com/rarimo/relayer-svc/internal/tracing"
 )
 
 const (
@@ -43,7 +47,9 @@ type scheduler struct {
 	log        *logan.Entry
 	cosmos     client.ServiceClient
 	core       core.Core
-	relayQueue rmq.Queue
+	relayQueue func(chain string) rmq.Queue
+	deadLetter func(ctx context.Context, task []byte, reason string) error
+	chains     *config.Chains
 	redis      *redis.Client
 }
 
@@ -55,7 +61,9 @@ func newScheduler(cfg config.Config) *scheduler {
 	return &scheduler{
 		log:        cfg.Log().WithField("service", "scheduler"),
 		cosmos:     client.NewServiceClient(cfg.Cosmos()),
-		relayQueue: cfg.Redis().OpenRelayQueue(),
+		relayQueue: cfg.Redis().OpenRelayQueue,
+		deadLetter: cfg.Redis().DeadLetterTask,
+		chains:     cfg.Chains(),
 		redis:      cfg.Redis().Client(),
 		core:       core.NewCore(cfg),
 		cfg:        cfg.Scheduler(),
@@ -197,7 +205,10 @@ func (s *scheduler) ScheduleRelays(
 	ctx context.Context,
 	confirmationID string,
 	transferIndexes []string,
-) error {
+) (err error) {
+	ctx, span := tracing.Start(ctx, "scheduler.ScheduleRelays", attribute.String("merkle_root", confirmationID))
+	defer func() { tracing.End(span, err) }()
+
 	log := s.log.WithField("merkle_root", confirmationID)
 	log.Info("processing a confirmation")
 
@@ -211,26 +222,44 @@ func (s *scheduler) ScheduleRelays(
 		if !slices.Contains(transferIndexes, transfer.Transfer.Origin) {
 			continue
 		}
-		tasks = append(tasks, data.NewRelayTask(transfer, relayer.MaxRetries))
+		tasks = append(tasks, data.NewRelayTask(ctx, transfer, relayer.MaxRetries))
 	}
 
-	rawTasks := [][]byte{}
+	// the tasks are published to the queues of their chains, so they are taken by the instances serving them
+	rawTasks := make(map[string][][]byte)
+	count := 0
 	for _, task := range tasks {
 		if slices.Contains(transferIndexes, task.OperationIndex) {
-			rawTasks = append(rawTasks, task.Marshal())
+			rawTasks[task.ToChain] = append(rawTasks[task.ToChain], task.Marshal())
+			count++
 		}
 	}
 
-	if len(rawTasks) == 0 {
+	span.SetAttributes(attribute.Int("tasks", count))
+	if count == 0 {
 		log.Info("no transfers to relay")
 		return nil
 	}
 
-	if err := s.relayQueue.PublishBytes(rawTasks...); err != nil {
-		return errors.Wrap(err, "failed to publish tasks")
+	for chain, chainTasks := range rawTasks {
+		// nothing consumes the queue of the chain no instance serves, so its tasks would be lost there
+		if !s.chains.Routable(chain) {
+			log.WithField("chain", chain).Errorf("chain is not served by any instance, dead-lettering %d transfers", len(chainTasks))
+			for _, task := range chainTasks {
+				if err := s.deadLetter(ctx, task, bridge.ErrUnknownChain.Error()); err != nil {
+					return errors.Wrap(err, "failed to dead-letter the task", logan.F{"chain": chain})
+				}
+			}
+			metrics.DeadLetteredTasks.WithLabelValues(chain).Add(float64(len(chainTasks)))
+			continue
+		}
+
+		if err := s.relayQueue(chain).PublishBytes(chainTasks...); err != nil {
+			return errors.Wrap(err, "failed to publish tasks", logan.F{"chain": chain})
+		}
 	}
 
-	log.Infof("scheduled %d transfers for relay", len(rawTasks))
+	log.Infof("scheduled %d transfers for relay", count)
 
 	return nil
 
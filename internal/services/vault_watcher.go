@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"os"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+	"gitlab.com/distributed_lab/running"
+
+	"github.com/rarimo/relayer-svc/internal/config"
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+	"github.com/rarimo/relayer-svc/internal/metrics"
+	"github.com/rarimo/relayer-svc/pkg/secret"
+)
+
+type vaultWatcher struct {
+	log      *logan.Entry
+	vault    secret.Vault
+	redis    redis.Rediser
+	instance string
+}
+
+// RunVaultWatcher keeps the Vault token alive and reloads the secret, so the rotated keys take effect
+// without a restart. The refresh failures are reported to the health endpoint under the instance host name.
+func RunVaultWatcher(cfg config.Config, ctx context.Context) {
+	instance, err := os.Hostname()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to get the instance host name"))
+	}
+
+	w := vaultWatcher{
+		log:      cfg.Log().WithFields(logan.F{"service": "vault_watcher", "instance": instance}),
+		vault:    cfg.Vault(),
+		redis:    cfg.Redis(),
+		instance: instance,
+	}
+
+	// the token is renewed once its ttl is within two intervals, so the failed refresh is retried within
+	// one interval to renew it before it expires
+	interval := w.vault.RefreshInterval()
+	running.WithBackOff(ctx, w.log, "run_once", w.runOnce, interval, interval/10, interval)
+}
+
+func (w *vaultWatcher) runOnce(ctx context.Context) error {
+	refreshErr := w.vault.Refresh(ctx)
+	metrics.VaultUp.Set(metrics.BoolToFloat(refreshErr == nil))
+
+	// the status outlives the longest retry period, so it expires only once the instance stops
+	interval := w.vault.RefreshInterval()
+	if err := w.redis.SetVaultStatus(ctx, w.instance, refreshErr, 3*interval); err != nil {
+		w.log.WithError(err).Error("failed to store the vault status")
+	}
+
+	if refreshErr != nil {
+		// the last loaded secret keeps being used until the refresh succeeds
+		return errors.Wrap(refreshErr, "failed to refresh the vault secret")
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/pkg/secret"
+)
+
+// local signs with the private keys of the Vault KV secret
+type local struct {
+	vault secret.Vault
+
+	mu     sync.Mutex
+	loaded *secret.Secret
+	keys   *Memory
+}
+
+func NewLocal(vault secret.Vault) Signer {
+	return &local{vault: vault}
+}
+
+func (l *local) Keys(ctx context.Context, set string) ([]Key, error) {
+	keys, err := l.memory()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys.Keys(ctx, set)
+}
+
+func (l *local) SignDigest(ctx context.Context, set, address string, digest []byte) ([]byte, error) {
+	keys, err := l.memory()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys.SignDigest(ctx, set, address, digest)
+}
+
+func (l *local) SignMessage(ctx context.Context, set, address string, message []byte) ([]byte, error) {
+	keys, err := l.memory()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys.SignMessage(ctx, set, address, message)
+}
+
+// memory returns the keys of the current secret, they are collected again once the secret is reloaded
+func (l *local) memory() (*Memory, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.vault.Secret()
+	if current == l.loaded && l.keys != nil {
+		return l.keys, nil
+	}
+
+	keys, err := memoryFromSecret(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect the secret keys")
+	}
+
+	l.loaded, l.keys = current, keys
+	return keys, nil
+}
+
+func memoryFromSecret(s *secret.Secret) (*Memory, error) {
+	keys := NewMemory()
+
+	if evm := s.EVM(); evm != nil {
+		for chain, chainKeys := range *evm {
+			for _, key := range chainKeys {
+				keys.AddSecp256k1(chain, crypto.PubkeyToAddress(key.PublicKey).Hex(), key)
+			}
+		}
+	}
+
+	if sol := s.Solana(); sol != nil {
+		for _, signer := range sol.Signers() {
+			key, err := sol.PrivateKey(signer)
+			if err != nil {
+				return nil, err
+			}
+
+			keys.AddEd25519(SetSolana, signer.String(), ed25519.PrivateKey(key))
+		}
+	}
+
+	if near := s.Near(); near != nil {
+		for _, signer := range near.Signers() {
+			key, err := near.PrivateKey(signer)
+			if err != nil {
+				return nil, err
+			}
+
+			keys.AddEd25519(SetNear, signer, key.PrivateKey)
+		}
+	}
+
+	if rarimo := s.Rarimo(); rarimo != nil {
+		for _, signer := range rarimo.Signers() {
+			key, err := rarimo.PrivateKey(signer)
+			if err != nil {
+				return nil, err
+			}
+
+			ecdsaKey, err := crypto.ToECDSA(key.Key)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid rarimo private key")
+			}
+
+			keys.AddSecp256k1(SetRarimo, signer, ecdsaKey)
+		}
+	}
+
+	return keys, nil
+}
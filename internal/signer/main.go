@@ -0,0 +1,154 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+const (
+	// BackendLocal signs with the keys loaded from the Vault KV secret
+	BackendLocal = "local"
+	// BackendTransit signs with the Vault Transit keys, so the private keys never leave Vault
+	BackendTransit = "transit"
+)
+
+// The key sets of the non-EVM chains, the keys of the EVM chain are in the set named after the chain
+const (
+	SetSolana = "solana"
+	SetNear   = "near"
+	SetRarimo = "rarimo"
+)
+
+var (
+	// ErrUnknownKey is returned for the address that is not among the keys of the set, e.g. the rotated out one
+	ErrUnknownKey = errors.New("unknown signer key")
+	// ErrKeyType is returned when the key does not support the requested signature scheme
+	ErrKeyType = errors.New("signature scheme is not supported by the key")
+)
+
+// Key describes the signer key of a set
+type Key struct {
+	// Address is the account the key signs for in the chain format: checksum hex for EVM, bech32 for Rarimo,
+	// base58 for Solana and the account ID for NEAR
+	Address string
+	// PublicKey is the compressed secp256k1 or the raw ed25519 public key
+	PublicKey []byte
+}
+
+// Signer signs the withdrawal transactions, so the bridgers do not have to hold the private keys
+type Signer interface {
+	// Keys returns the keys of the set, the set of an EVM chain is named after the chain
+	Keys(ctx context.Context, set string) ([]Key, error)
+	// SignDigest signs the 32-byte digest with the secp256k1 key of the address, the signature is
+	// 65 bytes of [R || S || V] with the low S and the V of 0 or 1
+	SignDigest(ctx context.Context, set, address string, digest []byte) ([]byte, error)
+	// SignMessage signs the message with the ed25519 key of the address
+	SignMessage(ctx context.Context, set, address string, message []byte) ([]byte, error)
+}
+
+// SetOf returns the key set signing the withdrawals to the chain of the family
+func SetOf(chain string, family types.ChainFamily) string {
+	switch family {
+	case types.FamilySolana:
+		return SetSolana
+	case types.FamilyNear:
+		return SetNear
+	case types.FamilyRarimo:
+		return SetRarimo
+	default:
+		return chain
+	}
+}
+
+// IsEd25519 tells whether the set holds the ed25519 keys, the rest of the sets hold the secp256k1 ones
+func IsEd25519(set string) bool {
+	return set == SetSolana || set == SetNear
+}
+
+// Addresses returns the addresses of the keys
+func Addresses(keys []Key) []string {
+	addresses := make([]string, len(keys))
+	for i, key := range keys {
+		addresses[i] = key.Address
+	}
+
+	return addresses
+}
+
+// Find returns the key of the address
+func Find(keys []Key, address string) (Key, error) {
+	for _, key := range keys {
+		if key.Address == address {
+			return key, nil
+		}
+	}
+
+	return Key{}, errors.Wrap(ErrUnknownKey, address)
+}
+
+// Lookup returns the key of the set signing the withdrawals to the chain with the address, the EVM addresses
+// match regardless of the checksum, so the address typed by an admin resolves to the one the bridgers use
+func Lookup(ctx context.Context, s Signer, chain string, family types.ChainFamily, address string) (Key, error) {
+	keys, err := s.Keys(ctx, SetOf(chain, family))
+	if err != nil {
+		return Key{}, errors.Wrap(err, "failed to get the signer keys")
+	}
+
+	for _, key := range keys {
+		if key.Address == address || common.IsHexAddress(address) && strings.EqualFold(key.Address, address) {
+			return key, nil
+		}
+	}
+
+	return Key{}, errors.Wrap(ErrUnknownKey, address)
+}
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// recoverable turns the [R || S] signature made by a remote backend into the [R || S || V] one, the S is
+// normalized to the lower half of the curve order as required by both Ethereum and Cosmos, and the recovery
+// id is the one that recovers the public key of the signer
+func recoverable(digest, signature, compressed []byte) ([]byte, error) {
+	if len(signature) != 64 {
+		return nil, errors.Errorf("expected a 64-byte signature, got %d bytes", len(signature))
+	}
+
+	s := new(big.Int).SetBytes(signature[32:])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+
+	result := make([]byte, 65)
+	copy(result, signature[:32])
+	s.FillBytes(result[32:64])
+
+	for v := byte(0); v < 2; v++ {
+		result[64] = v
+		recovered, err := crypto.Ecrecover(digest, result)
+		if err != nil {
+			continue
+		}
+
+		pub, err := crypto.UnmarshalPubkey(recovered)
+		if err != nil {
+			continue
+		}
+
+		if bytes.Equal(crypto.CompressPubkey(pub), compressed) {
+			return result, nil
+		}
+	}
+
+	return nil, errors.New("signature does not recover the signer public key")
+}
@@ -0,0 +1,243 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/types"
+)
+
+func TestRecoverable(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := crypto.Keccak256([]byte("withdrawal"))
+	signed, err := crypto.Sign(digest, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the high S twin of the signature is valid for the same key, remote backends may return either
+	highS := make([]byte, 64)
+	copy(highS, signed[:32])
+	new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(signed[32:64])).FillBytes(highS[32:])
+
+	cases := []struct {
+		name      string
+		signature []byte
+		publicKey []byte
+		wantErr   bool
+	}{
+		{
+			name:      "low S",
+			signature: signed[:64],
+			publicKey: crypto.CompressPubkey(&key.PublicKey),
+		},
+		{
+			name:      "high S is normalized",
+			signature: highS,
+			publicKey: crypto.CompressPubkey(&key.PublicKey),
+		},
+		{
+			name:      "other key",
+			signature: signed[:64],
+			publicKey: crypto.CompressPubkey(&other.PublicKey),
+			wantErr:   true,
+		},
+		{
+			name:      "recoverable signature is rejected",
+			signature: signed,
+			publicKey: crypto.CompressPubkey(&key.PublicKey),
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := recoverable(digest, tc.signature, tc.publicKey)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, signed) {
+				t.Fatalf("got signature %x, want %x", got, signed)
+			}
+		})
+	}
+}
+
+func TestMemory(t *testing.T) {
+	const (
+		evmSet     = "ethereum"
+		evmAddress = "0x52908400098527886E0F7030069857D2E4169EE7"
+		solAddress = "solana-signer"
+	)
+
+	secpKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	memory := NewMemory()
+	memory.AddSecp256k1(evmSet, evmAddress, secpKey)
+	memory.AddEd25519(SetSolana, solAddress, edKey)
+
+	digest := crypto.Keccak256([]byte("withdrawal"))
+	message := []byte("withdrawal")
+
+	cases := []struct {
+		name    string
+		sign    func() ([]byte, error)
+		verify  func(signature []byte) bool
+		wantErr error
+	}{
+		{
+			name: "secp256k1 digest",
+			sign: func() ([]byte, error) { return memory.SignDigest(ctx, evmSet, evmAddress, digest) },
+			verify: func(signature []byte) bool {
+				recovered, err := crypto.SigToPub(digest, signature)
+				return err == nil && crypto.PubkeyToAddress(*recovered) == crypto.PubkeyToAddress(secpKey.PublicKey)
+			},
+		},
+		{
+			name: "ed25519 message",
+			sign: func() ([]byte, error) { return memory.SignMessage(ctx, SetSolana, solAddress, message) },
+			verify: func(signature []byte) bool {
+				return ed25519.Verify(edKey.Public().(ed25519.PublicKey), message, signature)
+			},
+		},
+		{
+			name:    "message with the secp256k1 key",
+			sign:    func() ([]byte, error) { return memory.SignMessage(ctx, evmSet, evmAddress, message) },
+			wantErr: ErrKeyType,
+		},
+		{
+			name:    "digest with the ed25519 key",
+			sign:    func() ([]byte, error) { return memory.SignDigest(ctx, SetSolana, solAddress, digest) },
+			wantErr: ErrKeyType,
+		},
+		{
+			name:    "unknown address",
+			sign:    func() ([]byte, error) { return memory.SignDigest(ctx, evmSet, solAddress, digest) },
+			wantErr: ErrUnknownKey,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			signature, err := tc.sign()
+			if tc.wantErr != nil {
+				if errors.Cause(err) != tc.wantErr {
+					t.Fatalf("got error %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.verify(signature) {
+				t.Fatal("signature does not verify")
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	const (
+		evmAddress = "0x52908400098527886E0F7030069857D2E4169EE7"
+		solAddress = "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"
+	)
+
+	secpKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memory := NewMemory()
+	memory.AddSecp256k1("ethereum", evmAddress, secpKey)
+	memory.AddEd25519(SetSolana, solAddress, edKey)
+
+	cases := []struct {
+		name    string
+		chain   string
+		family  types.ChainFamily
+		address string
+		want    string
+		wantErr error
+	}{
+		{name: "checksummed evm", chain: "ethereum", family: types.FamilyEVM, address: evmAddress, want: evmAddress},
+		{
+			name:    "lowercase evm",
+			chain:   "ethereum",
+			family:  types.FamilyEVM,
+			address: "0x52908400098527886e0f7030069857d2e4169ee7",
+			want:    evmAddress,
+		},
+		{
+			name:    "evm of another chain",
+			chain:   "polygon",
+			family:  types.FamilyEVM,
+			address: evmAddress,
+			wantErr: ErrUnknownKey,
+		},
+		{name: "solana", chain: "solana-mainnet", family: types.FamilySolana, address: solAddress, want: solAddress},
+		{
+			name:    "solana of another case",
+			chain:   "solana-mainnet",
+			family:  types.FamilySolana,
+			address: "9XQEWVG816BUX9EPJHMAT23YVVM2ZWBRRPZB9PUSVFIN",
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name:    "mistyped evm",
+			chain:   "ethereum",
+			family:  types.FamilyEVM,
+			address: "0x52908400098527886E0F7030069857D2E4169EE8",
+			wantErr: ErrUnknownKey,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := Lookup(context.Background(), memory, tc.chain, tc.family, tc.address)
+			if tc.wantErr != nil {
+				if errors.Cause(err) != tc.wantErr {
+					t.Fatalf("got error %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key.Address != tc.want {
+				t.Fatalf("got %s, want %s", key.Address, tc.want)
+			}
+		})
+	}
+}
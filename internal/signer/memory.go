@@ -0,0 +1,105 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Memory holds the private keys in memory, it backs the local signer and stands in for the remote one in tests
+type Memory struct {
+	mu   sync.RWMutex
+	sets map[string][]memoryKey
+}
+
+type memoryKey struct {
+	Key
+	secp256k1 *ecdsa.PrivateKey
+	ed25519   ed25519.PrivateKey
+}
+
+func NewMemory() *Memory {
+	return &Memory{sets: make(map[string][]memoryKey)}
+}
+
+// AddSecp256k1 adds the secp256k1 key signing for the address to the set
+func (m *Memory) AddSecp256k1(set, address string, key *ecdsa.PrivateKey) {
+	m.add(set, memoryKey{
+		Key:       Key{Address: address, PublicKey: crypto.CompressPubkey(&key.PublicKey)},
+		secp256k1: key,
+	})
+}
+
+// AddEd25519 adds the ed25519 key signing for the address to the set
+func (m *Memory) AddEd25519(set, address string, key ed25519.PrivateKey) {
+	m.add(set, memoryKey{
+		Key:     Key{Address: address, PublicKey: key.Public().(ed25519.PublicKey)},
+		ed25519: key,
+	})
+}
+
+func (m *Memory) add(set string, key memoryKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sets[set] = append(m.sets[set], key)
+}
+
+func (m *Memory) Keys(_ context.Context, set string) ([]Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]Key, len(m.sets[set]))
+	for i, key := range m.sets[set] {
+		keys[i] = key.Key
+	}
+
+	return keys, nil
+}
+
+func (m *Memory) SignDigest(_ context.Context, set, address string, digest []byte) ([]byte, error) {
+	key, err := m.find(set, address)
+	if err != nil {
+		return nil, err
+	}
+	if key.secp256k1 == nil {
+		return nil, errors.From(ErrKeyType, logan.F{"set": set, "address": address})
+	}
+
+	signature, err := crypto.Sign(digest, key.secp256k1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign the digest")
+	}
+
+	return signature, nil
+}
+
+func (m *Memory) SignMessage(_ context.Context, set, address string, message []byte) ([]byte, error) {
+	key, err := m.find(set, address)
+	if err != nil {
+		return nil, err
+	}
+	if key.ed25519 == nil {
+		return nil, errors.From(ErrKeyType, logan.F{"set": set, "address": address})
+	}
+
+	return ed25519.Sign(key.ed25519, message), nil
+}
+
+func (m *Memory) find(set, address string) (memoryKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.sets[set] {
+		if key.Address == address {
+			return key, nil
+		}
+	}
+
+	return memoryKey{}, errors.From(ErrUnknownKey, logan.F{"set": set, "address": address})
+}
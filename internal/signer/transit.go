@@ -0,0 +1,331 @@
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/olegfomenko/solana-go"
+	"github.com/spf13/cast"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const defaultTransitMount = "transit"
+
+type TransitConfig struct {
+	// Mount is the path the Transit secrets engine holding the ed25519 keys is mounted at
+	Mount string `fig:"mount"`
+	// Secp256k1Mount is the path of the Transit compatible engine holding the secp256k1 keys, the stock
+	// Transit does not support the curve, so it is usually a plugin. Mount is used by default.
+	Secp256k1Mount string       `fig:"secp256k1_mount"`
+	Keys           []TransitKey `fig:"keys,required"`
+	// RefreshInterval is how long the public keys are cached, so the rotated Transit keys are picked up. The
+	// Vault refresh interval is used by default.
+	RefreshInterval time.Duration `fig:"refresh_interval"`
+}
+
+// TransitKey binds the Transit key to the key set it signs for
+type TransitKey struct {
+	Set  string `fig:"set,required"`
+	Name string `fig:"name,required"`
+	// Account is the NEAR account the key is an access key of, the implicit account of the key by default
+	Account string `fig:"account"`
+}
+
+// transit signs with the Vault Transit keys, only the public keys and the signatures leave Vault
+type transit struct {
+	client *vaultapi.Client
+	cfg    TransitConfig
+
+	mu   sync.Mutex
+	sets map[string]transitSet
+}
+
+type transitSet struct {
+	keys   []transitKey
+	readAt time.Time
+}
+
+type transitKey struct {
+	Key
+	name string
+}
+
+func NewTransit(client *vaultapi.Client, cfg TransitConfig) Signer {
+	if cfg.Mount == "" {
+		cfg.Mount = defaultTransitMount
+	}
+	if cfg.Secp256k1Mount == "" {
+		cfg.Secp256k1Mount = cfg.Mount
+	}
+
+	return &transit{
+		client: client,
+		cfg:    cfg,
+		sets:   make(map[string]transitSet),
+	}
+}
+
+func (t *transit) Keys(ctx context.Context, set string) ([]Key, error) {
+	keys, err := t.setKeys(ctx, set)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Key, len(keys))
+	for i, key := range keys {
+		result[i] = key.Key
+	}
+
+	return result, nil
+}
+
+func (t *transit) SignDigest(ctx context.Context, set, address string, digest []byte) ([]byte, error) {
+	if IsEd25519(set) {
+		return nil, errors.From(ErrKeyType, logan.F{"set": set, "address": address})
+	}
+
+	key, err := t.find(ctx, set, address)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := t.sign(ctx, t.cfg.Secp256k1Mount, key.name, map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"marshaling_algorithm": "jws",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := recoverable(digest, signature, key.PublicKey)
+	if err != nil {
+		// the key is likely rotated, so the cached public key is stale
+		t.invalidate(set)
+		return nil, errors.Wrap(err, "failed to verify the transit signature", logan.F{"set": set, "key": key.name})
+	}
+
+	return result, nil
+}
+
+func (t *transit) SignMessage(ctx context.Context, set, address string, message []byte) ([]byte, error) {
+	if !IsEd25519(set) {
+		return nil, errors.From(ErrKeyType, logan.F{"set": set, "address": address})
+	}
+
+	key, err := t.find(ctx, set, address)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := t.sign(ctx, t.cfg.Mount, key.name, map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !ed25519.Verify(key.PublicKey, message, signature) {
+		t.invalidate(set)
+		return nil, errors.From(errors.New("transit signature does not match the signer public key"),
+			logan.F{"set": set, "key": key.name})
+	}
+
+	return signature, nil
+}
+
+func (t *transit) find(ctx context.Context, set, address string) (transitKey, error) {
+	keys, err := t.setKeys(ctx, set)
+	if err != nil {
+		return transitKey{}, err
+	}
+
+	for _, key := range keys {
+		if key.Address == address {
+			return key, nil
+		}
+	}
+
+	return transitKey{}, errors.From(ErrUnknownKey, logan.F{"set": set, "address": address})
+}
+
+// setKeys reads the public keys of the set and caches them for the refresh interval, the addresses are
+// derived from them
+func (t *transit) setKeys(ctx context.Context, set string) ([]transitKey, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cached, ok := t.sets[set]; ok && time.Since(cached.readAt) < t.cfg.RefreshInterval {
+		return cached.keys, nil
+	}
+
+	keys := make([]transitKey, 0)
+	for _, configured := range t.cfg.Keys {
+		if configured.Set != set {
+			continue
+		}
+
+		key, err := t.readKey(ctx, configured)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the transit key", logan.F{"set": set, "key": configured.Name})
+		}
+
+		keys = append(keys, key)
+	}
+
+	t.sets[set] = transitSet{keys: keys, readAt: time.Now()}
+	return keys, nil
+}
+
+// invalidate drops the cached public keys of the set, so they are read again on the next use
+func (t *transit) invalidate(set string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.sets, set)
+}
+
+func (t *transit) readKey(ctx context.Context, configured TransitKey) (transitKey, error) {
+	mount := t.cfg.Secp256k1Mount
+	if IsEd25519(configured.Set) {
+		mount = t.cfg.Mount
+	}
+
+	resp, err := t.client.Logical().ReadWithContext(ctx, fmt.Sprintf("%s/keys/%s", mount, configured.Name))
+	if err != nil {
+		return transitKey{}, errors.Wrap(err, "failed to read the key")
+	}
+	if resp == nil || resp.Data == nil {
+		return transitKey{}, errors.New("key is not found")
+	}
+
+	versions, err := cast.ToStringMapE(resp.Data["keys"])
+	if err != nil {
+		return transitKey{}, errors.Wrap(err, "unexpected key versions")
+	}
+	latest, err := cast.ToStringMapE(versions[cast.ToString(resp.Data["latest_version"])])
+	if err != nil {
+		return transitKey{}, errors.Wrap(err, "unexpected latest key version")
+	}
+	rawPublicKey := cast.ToString(latest["public_key"])
+
+	key := transitKey{name: configured.Name}
+	switch configured.Set {
+	case SetSolana, SetNear:
+		key.PublicKey, err = base64.StdEncoding.DecodeString(rawPublicKey)
+		if err != nil || len(key.PublicKey) != ed25519.PublicKeySize {
+			return transitKey{}, errors.New("expected a base64-encoded ed25519 public key")
+		}
+
+		if configured.Set == SetSolana {
+			key.Address = solana.PublicKeyFromBytes(key.PublicKey).String()
+		} else if key.Address = configured.Account; key.Address == "" {
+			key.Address = hex.EncodeToString(key.PublicKey)
+		}
+	case SetRarimo:
+		if key.PublicKey, err = parseSecp256k1PublicKey(rawPublicKey); err != nil {
+			return transitKey{}, err
+		}
+
+		pub := secp256k1.PubKey{Key: key.PublicKey}
+		if key.Address, err = bech32.ConvertAndEncode("rarimo", pub.Address().Bytes()); err != nil {
+			return transitKey{}, errors.Wrap(err, "failed to convert rarimo address")
+		}
+	default:
+		if key.PublicKey, err = parseSecp256k1PublicKey(rawPublicKey); err != nil {
+			return transitKey{}, err
+		}
+
+		pub, err := crypto.DecompressPubkey(key.PublicKey)
+		if err != nil {
+			return transitKey{}, errors.Wrap(err, "invalid secp256k1 public key")
+		}
+		key.Address = crypto.PubkeyToAddress(*pub).Hex()
+	}
+
+	return key, nil
+}
+
+// sign returns the raw signature of the Transit key, the signature is prefixed with the key version by Vault
+func (t *transit) sign(ctx context.Context, mount, name string, data map[string]interface{}) ([]byte, error) {
+	resp, err := t.client.Logical().WriteWithContext(ctx, fmt.Sprintf("%s/sign/%s", mount, name), data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign with the transit key", logan.F{"key": name})
+	}
+	if resp == nil || resp.Data == nil {
+		return nil, errors.From(errors.New("empty transit sign response"), logan.F{"key": name})
+	}
+
+	parts := strings.Split(cast.ToString(resp.Data["signature"]), ":")
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, errors.From(errors.New("unexpected transit signature format"), logan.F{"key": name})
+	}
+
+	encoding := base64.StdEncoding
+	if data["marshaling_algorithm"] == "jws" {
+		encoding = base64.RawURLEncoding
+	}
+
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode the transit signature", logan.F{"key": name})
+	}
+
+	return signature, nil
+}
+
+// parseSecp256k1PublicKey accepts either the PEM-encoded public key or the base64-encoded point, the compressed
+// form of the key is returned
+func parseSecp256k1PublicKey(raw string) ([]byte, error) {
+	var point []byte
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		// the generic x509 parser does not know the secp256k1 curve, so the point is taken from the
+		// SubjectPublicKeyInfo as is
+		var info struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}
+		if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the PEM-encoded public key")
+		}
+
+		point = info.PublicKey.Bytes
+	} else {
+		var err error
+		if point, err = base64.StdEncoding.DecodeString(raw); err != nil {
+			return nil, errors.Wrap(err, "expected a PEM or base64-encoded secp256k1 public key")
+		}
+	}
+
+	switch len(point) {
+	case 33:
+		if _, err := crypto.DecompressPubkey(point); err != nil {
+			return nil, errors.Wrap(err, "invalid secp256k1 public key")
+		}
+
+		return point, nil
+	case 65:
+		pub, err := crypto.UnmarshalPubkey(point)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid secp256k1 public key")
+		}
+
+		return crypto.CompressPubkey(pub), nil
+	default:
+		return nil, errors.Errorf("unexpected secp256k1 public key length %d", len(point))
+	}
+}
@@ -0,0 +1,163 @@
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/olegfomenko/solana-go"
+)
+
+// fakeTransit serves the ed25519 Transit key the way Vault does, the key can be rotated underneath the signer
+type fakeTransit struct {
+	mu  sync.Mutex
+	key ed25519.PrivateKey
+}
+
+func (f *fakeTransit) rotate(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.key = key
+}
+
+func (f *fakeTransit) address() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return solana.PublicKeyFromBytes(f.key.Public().(ed25519.PublicKey)).String()
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var data map[string]interface{}
+	switch r.URL.Path {
+	case "/v1/transit/keys/relayer-solana":
+		data = map[string]interface{}{
+			"latest_version": 1,
+			"keys": map[string]interface{}{
+				"1": map[string]interface{}{
+					"public_key": base64.StdEncoding.EncodeToString(f.key.Public().(ed25519.PublicKey)),
+				},
+			},
+		}
+	case "/v1/transit/sign/relayer-solana":
+		var body struct {
+			Input string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input, err := base64.StdEncoding.DecodeString(body.Input)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		data = map[string]interface{}{
+			"signature": "vault:v1:" + base64.StdEncoding.EncodeToString(ed25519.Sign(f.key, input)),
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func TestTransitKeyRotation(t *testing.T) {
+	cases := []struct {
+		name            string
+		refreshInterval time.Duration
+		// sign signs with the cached address after the rotation, the failed verification drops the cache
+		sign        bool
+		wantRotated bool
+	}{
+		{
+			name:            "cached within the refresh interval",
+			refreshInterval: time.Hour,
+		},
+		{
+			name:            "read again after the refresh interval",
+			refreshInterval: time.Nanosecond,
+			wantRotated:     true,
+		},
+		{
+			name:            "read again after the failed verification",
+			refreshInterval: time.Hour,
+			sign:            true,
+			wantRotated:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := new(fakeTransit)
+			fake.rotate(t)
+
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			config := vaultapi.DefaultConfig()
+			config.Address = server.URL
+			client, err := vaultapi.NewClient(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.SetToken("test")
+
+			ctx := context.Background()
+			transit := NewTransit(client, TransitConfig{
+				Keys:            []TransitKey{{Set: SetSolana, Name: "relayer-solana"}},
+				RefreshInterval: tc.refreshInterval,
+			})
+
+			keys, err := transit.Keys(ctx, SetSolana)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != 1 || keys[0].Address != fake.address() {
+				t.Fatalf("got keys %v, want the address %s", keys, fake.address())
+			}
+			if _, err = transit.SignMessage(ctx, SetSolana, keys[0].Address, []byte("withdrawal")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			stale := keys[0].Address
+			fake.rotate(t)
+
+			if tc.sign {
+				if _, err = transit.SignMessage(ctx, SetSolana, stale, []byte("withdrawal")); err == nil {
+					t.Fatal("expected the signature made by the rotated key to fail the verification")
+				}
+			}
+
+			keys, err = transit.Keys(ctx, SetSolana)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := stale
+			if tc.wantRotated {
+				want = fake.address()
+			}
+			if len(keys) != 1 || keys[0].Address != want {
+				t.Fatalf("got keys %v, want the address %s", keys, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package signerpool
+
+import (
+	"context"
+	"sync"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+)
+
+const (
+	// StrategyLeastLoaded selects the signer with the fewest withdrawals awaiting the confirmation
+	StrategyLeastLoaded = "least_loaded"
+	// StrategyRoundRobin selects the signers in turn
+	StrategyRoundRobin = "round_robin"
+)
+
+// ErrAllDrained is returned when every signer of the chain is drained, the withdrawal waits for the signers
+// to be funded or undrained
+var ErrAllDrained = errors.New("all the chain signers are drained")
+
+// Pool selects the signer for the next withdrawal to the chain among the keys of its pool. The drained signers
+// and the loads are kept in redis, so they are shared by the replicas.
+type Pool struct {
+	strategy string
+	redis    redis.Rediser
+
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func New(strategy string, rediser redis.Rediser) *Pool {
+	return &Pool{
+		strategy: strategy,
+		redis:    rediser,
+		next:     make(map[string]int),
+	}
+}
+
+// Select returns the signer for the next withdrawal to the chain, the pool is the list of the chain signers
+func (p *Pool) Select(ctx context.Context, chain string, pool []string) (string, error) {
+	if len(pool) == 1 {
+		// the only key is used even if it is drained, as there is nothing to rotate it with
+		return pool[0], nil
+	}
+
+	drained, err := p.redis.DrainedSigners(ctx, chain)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the drained signers")
+	}
+
+	var loads map[string]int64
+	if p.strategy == StrategyLeastLoaded {
+		if loads, err = p.redis.SignerLoads(ctx, chain); err != nil {
+			return "", errors.Wrap(err, "failed to get the signer loads")
+		}
+	}
+
+	// starting from the next signer in turn, so the equally loaded signers are used evenly
+	start := p.turn(chain, len(pool))
+	selected, minLoad := "", int64(0)
+	for i := range pool {
+		signer := pool[(start+i)%len(pool)]
+		if _, isDrained := drained[redis.NormalizeSigner(signer)]; isDrained {
+			continue
+		}
+
+		load := lookupLoad(loads, signer)
+		if selected == "" || load < minLoad {
+			selected, minLoad = signer, load
+		}
+	}
+
+	if selected == "" {
+		return "", errors.From(ErrAllDrained, logan.F{"chain": chain})
+	}
+
+	return selected, nil
+}
+
+// Acquired counts the withdrawal submitted by the signer until it is Released
+func (p *Pool) Acquired(ctx context.Context, chain, signer string) error {
+	return p.redis.AddSignerLoad(ctx, chain, signer, 1)
+}
+
+// Released stops counting the withdrawal that is final or is going to be submitted again
+func (p *Pool) Released(ctx context.Context, chain, signer string) error {
+	return p.redis.AddSignerLoad(ctx, chain, signer, -1)
+}
+
+func (p *Pool) turn(chain string, size int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	turn := p.next[chain] % size
+	p.next[chain] = turn + 1
+
+	return turn
+}
+
+// lookupLoad treats the negative loads as zero, as the counters may drift when the replica stops halfway
+func lookupLoad(loads map[string]int64, signer string) int64 {
+	if load := loads[redis.NormalizeSigner(signer)]; load > 0 {
+		return load
+	}
+
+	return 0
+}
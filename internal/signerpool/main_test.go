@@ -0,0 +1,137 @@
+package signerpool
+
+import (
+	"context"
+	"testing"
+
+	"gitlab.com/distributed_lab/logan/v3/errors"
+
+	"github.com/rarimo/relayer-svc/internal/data/redis"
+)
+
+// fakeRediser serves the drains and the loads the way the redis storage returns them
+type fakeRediser struct {
+	redis.Rediser
+	drained map[string]string
+	loads   map[string]int64
+}
+
+func (f fakeRediser) DrainedSigners(context.Context, string) (map[string]string, error) {
+	return f.drained, nil
+}
+
+func (f fakeRediser) SignerLoads(context.Context, string) (map[string]int64, error) {
+	return f.loads, nil
+}
+
+func TestSelect(t *testing.T) {
+	const (
+		first  = "0x52908400098527886E0F7030069857D2E4169EE7"
+		second = "0x8617E340B3D01FA5F11F306F4090FD50E238070D"
+		third  = "0xde709f2102306220921060314715629080e2fb77"
+	)
+	normalized := redis.NormalizeSigner
+
+	cases := []struct {
+		name     string
+		strategy string
+		pool     []string
+		drained  map[string]string
+		loads    map[string]int64
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "single signer is used even if drained",
+			strategy: StrategyLeastLoaded,
+			pool:     []string{first},
+			drained:  map[string]string{normalized(first): redis.DrainReasonManual},
+			want:     first,
+		},
+		{
+			name:     "least loaded",
+			strategy: StrategyLeastLoaded,
+			pool:     []string{first, second, third},
+			loads:    map[string]int64{normalized(first): 3, normalized(second): 1, normalized(third): 2},
+			want:     second,
+		},
+		{
+			name:     "negative loads count as zero",
+			strategy: StrategyLeastLoaded,
+			pool:     []string{first, second},
+			loads:    map[string]int64{normalized(first): -5, normalized(second): 1},
+			want:     first,
+		},
+		{
+			name:     "drained signer is skipped regardless of the address case",
+			strategy: StrategyLeastLoaded,
+			pool:     []string{first, second},
+			drained:  map[string]string{normalized(first): redis.DrainReasonLowFunds},
+			loads:    map[string]int64{normalized(second): 10},
+			want:     second,
+		},
+		{
+			name:     "all drained",
+			strategy: StrategyRoundRobin,
+			pool:     []string{first, second},
+			drained: map[string]string{
+				normalized(first):  redis.DrainReasonManual,
+				normalized(second): redis.DrainReasonManual,
+			},
+			wantErr: ErrAllDrained,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := New(tc.strategy, fakeRediser{drained: tc.drained, loads: tc.loads})
+
+			got, err := pool.Select(context.Background(), "Goerli", tc.pool)
+			if tc.wantErr != nil {
+				if errors.Cause(err) != tc.wantErr {
+					t.Fatalf("expected %v, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSelectRoundRobin(t *testing.T) {
+	pool := New(StrategyRoundRobin, fakeRediser{})
+	signers := []string{"a", "b", "c"}
+
+	for i, want := range []string{"a", "b", "c", "a"} {
+		got, err := pool.Select(context.Background(), "Solana", signers)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Fatalf("selection %d: expected %s, got %s", i, want, got)
+		}
+	}
+}
+
+func TestNormalizeSigner(t *testing.T) {
+	cases := []struct {
+		signer string
+		want   string
+	}{
+		{signer: "0x52908400098527886E0F7030069857D2E4169EE7", want: "0x52908400098527886e0f7030069857d2e4169ee7"},
+		// the base58 keys are case-sensitive
+		{signer: "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin", want: "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"},
+		{signer: "relayer.testnet", want: "relayer.testnet"},
+	}
+
+	for _, tc := range cases {
+		if got := redis.NormalizeSigner(tc.signer); got != tc.want {
+			t.Errorf("NormalizeSigner(%s) = %s, want %s", tc.signer, got, tc.want)
+		}
+	}
+}
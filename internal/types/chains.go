@@ -1,31 +1,27 @@
 package types
 
-import "golang.org/x/exp/slices"
+// ChainFamily defines the bridger that relays the transfers to the chain
+type ChainFamily string
 
 const (
-	EthereumMainnet = "Ethereum"
-	Goerli          = "Goerli"
-	Sepolia         = "Sepolia"
-
-	MaticMainnet = "Matic"
-	Mumbai       = "Mumbai"
-
-	BSCMainnet = "BSC"
-	Chapel     = "Chapel"
-
-	AvalancheMainnet = "Avalanche"
-	Fuji             = "Fuji"
+	FamilyEVM    ChainFamily = "evm"
+	FamilySolana ChainFamily = "solana"
+	FamilyNear   ChainFamily = "near"
+	FamilyRarimo ChainFamily = "rarimo"
+)
 
+// Default names of the non-EVM chains, used when the chains are not declared in the config
+const (
 	Solana = "Solana"
 	Near   = "Near"
-
 	Rarimo = "Rarimo"
 )
 
-var evmChains = []string{
-	EthereumMainnet, Goerli, Sepolia, MaticMainnet, Mumbai, BSCMainnet, Chapel, AvalancheMainnet, Fuji,
-}
-
-func IsEVM(chain string) bool {
-	return slices.Contains(evmChains, chain)
+func (f ChainFamily) IsValid() bool {
+	switch f {
+	case FamilyEVM, FamilySolana, FamilyNear, FamilyRarimo:
+		return true
+	default:
+		return false
+	}
 }
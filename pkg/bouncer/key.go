@@ -22,7 +22,12 @@ func GenerateJWT(cfg Config, vault secret.Vault, log *logan.Entry) {
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
 
-	tokenString, err := token.SignedString(vault.Secret().Bouncer())
+	key := vault.Secret().Bouncer()
+	if key == nil {
+		panic(errors.Wrap(secret.ErrUninitializedPrivateKey, "bouncer private key is not in the secret"))
+	}
+
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		panic(errors.Wrap(err, "failed to sign token"))
 	}
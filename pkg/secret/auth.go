@@ -0,0 +1,99 @@
+package secret
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// login authenticates the client with the configured auth method
+func (v *vault) login(ctx context.Context) error {
+	if v.cfg.Auth == AuthToken {
+		v.api.SetToken(v.cfg.Token)
+		return nil
+	}
+
+	var data map[string]interface{}
+	switch v.cfg.Auth {
+	case AuthAppRole:
+		secretID := v.cfg.SecretID
+		if v.cfg.SecretIDFile != "" {
+			raw, err := os.ReadFile(v.cfg.SecretIDFile)
+			if err != nil {
+				return errors.Wrap(err, "failed to read the secret id file")
+			}
+			secretID = strings.TrimSpace(string(raw))
+		}
+
+		data = map[string]interface{}{"role_id": v.cfg.RoleID, "secret_id": secretID}
+	case AuthKubernetes:
+		jwt, err := os.ReadFile(v.cfg.JWTPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the service account token")
+		}
+
+		data = map[string]interface{}{"role": v.cfg.Role, "jwt": strings.TrimSpace(string(jwt))}
+	}
+
+	resp, err := v.api.Logical().WriteWithContext(ctx, "auth/"+v.cfg.AuthMount+"/login", data)
+	if err != nil {
+		return errors.Wrap(err, "failed to log in", logan.F{"auth": v.cfg.Auth})
+	}
+	if resp == nil || resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return errors.From(errors.New("login response has no token"), logan.F{"auth": v.cfg.Auth})
+	}
+
+	v.api.SetToken(resp.Auth.ClientToken)
+	v.log.WithFields(logan.F{"auth": v.cfg.Auth, "ttl": resp.Auth.LeaseDuration}).Info("[Vault] Logged in")
+
+	return nil
+}
+
+// keepToken renews the token that would expire before the next refresh, the token that can not be renewed
+// any longer is replaced by logging in again, except for the static one
+func (v *vault) keepToken(ctx context.Context) error {
+	self, err := v.api.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		if v.cfg.Auth == AuthToken {
+			return errors.Wrap(err, "failed to look up the token")
+		}
+
+		v.log.WithError(err).Warn("[Vault] Failed to look up the token, logging in again")
+		return v.login(ctx)
+	}
+
+	ttl, err := self.TokenTTL()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the token ttl")
+	}
+	// the token without the ttl never expires
+	if ttl == 0 || ttl > 2*v.cfg.RefreshInterval {
+		return nil
+	}
+
+	renewable, err := self.TokenIsRenewable()
+	if err != nil {
+		return errors.Wrap(err, "failed to check if the token is renewable")
+	}
+
+	if renewable {
+		renewed, err := v.api.Auth().Token().RenewSelfWithContext(ctx, 0)
+		switch {
+		case err != nil:
+			v.log.WithError(err).Warn("[Vault] Failed to renew the token")
+		case renewed.Auth != nil && renewed.Auth.LeaseDuration > int(2*v.cfg.RefreshInterval.Seconds()):
+			v.log.WithField("ttl", renewed.Auth.LeaseDuration).Debug("[Vault] Token renewed")
+			return nil
+		}
+	}
+
+	if v.cfg.Auth == AuthToken {
+		return errors.From(errors.New("token is about to expire and can not be renewed"), logan.F{"ttl": ttl.String()})
+	}
+
+	// the token has reached its max ttl
+	return v.login(ctx)
+}
@@ -1,6 +1,11 @@
 package secret
 
 import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
 	vaultapi "github.com/hashicorp/vault/api"
 	"gitlab.com/distributed_lab/figure/v3"
 	"gitlab.com/distributed_lab/kit/comfig"
@@ -9,11 +14,49 @@ import (
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
+const (
+	AuthToken      = "token"
+	AuthAppRole    = "approle"
+	AuthKubernetes = "kubernetes"
+
+	defaultRefreshInterval = 5 * time.Minute
+	defaultKubernetesJWT   = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	keystoreConfigKey = "keystore"
+)
+
 type VaultConfig struct {
 	Address string `fig:"address,required"`
 	Mount   string `fig:"mount,required"`
 	Secret  string `fig:"secret,required"`
-	Token   string `fig:"token,required"`
+	// Auth is the auth method: token, approle or kubernetes
+	Auth string `fig:"auth"`
+	// AuthMount is the path the auth method is mounted at, the method name by default
+	AuthMount string `fig:"auth_mount"`
+	// Token is used by the token auth method
+	Token string `fig:"token"`
+	// RoleID and SecretID are used by the approle auth method, the SecretIDFile is read on every login,
+	// so the rotated secret ID is picked up
+	RoleID       string `fig:"role_id"`
+	SecretID     string `fig:"secret_id"`
+	SecretIDFile string `fig:"secret_id_file"`
+	// Role and JWTPath are used by the kubernetes auth method
+	Role    string `fig:"role"`
+	JWTPath string `fig:"jwt_path"`
+	// RefreshInterval is how often the token is renewed and the secret is reloaded
+	RefreshInterval time.Duration `fig:"refresh_interval"`
+}
+
+// KeystoreConfig configures the local encrypted keystore used instead of Vault
+type KeystoreConfig struct {
+	Dir string `fig:"dir,required"`
+	// Password or the content of the PasswordFile decrypts the keys
+	Password     string `fig:"password"`
+	PasswordFile string `fig:"password_file"`
+	// LightKDF makes the imported keys cheaper to decrypt and to brute-force, for the test rigs only
+	LightKDF bool `fig:"light_kdf"`
+	// RefreshInterval is how often the keys are read again
+	RefreshInterval time.Duration `fig:"refresh_interval"`
 }
 
 type Vaulter interface {
@@ -35,14 +78,29 @@ type vaulter struct {
 
 func (v *vaulter) Vault() Vault {
 	return v.Do(func() interface{} {
-		var config VaultConfig
-		err := figure.
+		keystoreConfig, err := KeystoreConfigFrom(v.getter)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out keystore"))
+		}
+		if keystoreConfig != nil {
+			return v.keystore(keystoreConfig)
+		}
+
+		config := VaultConfig{
+			Auth:            AuthToken,
+			JWTPath:         defaultKubernetesJWT,
+			RefreshInterval: defaultRefreshInterval,
+		}
+		err = figure.
 			Out(&config).
 			From(kv.MustGetStringMap(v.getter, "vault")).
 			Please()
 		if err != nil {
 			panic(errors.Wrap(err, "failed to figure out vault"))
 		}
+		if err = config.validate(); err != nil {
+			panic(errors.Wrap(err, "invalid vault config"))
+		}
 
 		conf := vaultapi.DefaultConfig()
 		conf.Address = config.Address
@@ -51,14 +109,17 @@ func (v *vaulter) Vault() Vault {
 			panic(errors.Wrap(err, "failed to create vault client"))
 		}
 
-		client.SetToken(config.Token)
-
 		storage := &vault{
 			cfg:    &config,
+			api:    client,
 			client: client.KVv2(config.Mount),
-			log:    v.log,
+			log:    v.log.WithField("service", "vault"),
+		}
+		if err = storage.login(context.Background()); err != nil {
+			panic(errors.Wrap(err, "failed to log in to vault"))
 		}
-		err = storage.loadSecret()
+
+		err = storage.loadSecret(context.Background())
 		if err != nil {
 			panic(errors.Wrap(err, "failed to load secret"))
 		}
@@ -66,3 +127,83 @@ func (v *vaulter) Vault() Vault {
 		return storage
 	}).(Vault)
 }
+
+func (v *vaulter) keystore(config *KeystoreConfig) Vault {
+	storage := &localKeystore{
+		cfg: config,
+		log: v.log.WithField("service", "keystore"),
+	}
+	if err := storage.loadSecret(); err != nil {
+		panic(errors.Wrap(err, "failed to load keystore"))
+	}
+
+	return storage
+}
+
+// KeystoreConfigFrom returns the keystore config, nil is returned if the keystore is not configured,
+// so Vault is used
+func KeystoreConfigFrom(getter kv.Getter) (*KeystoreConfig, error) {
+	// the missing section is read as the empty map
+	raw := kv.MustGetStringMap(getter, keystoreConfigKey)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	config := KeystoreConfig{
+		RefreshInterval: defaultRefreshInterval,
+	}
+	if err := figure.Out(&config).From(raw).Please(); err != nil {
+		return nil, err
+	}
+	if config.Password == "" && config.PasswordFile == "" {
+		return nil, errors.New("either password or password_file is required for the keystore")
+	}
+	if config.RefreshInterval <= 0 {
+		return nil, errors.New("refresh interval must be positive")
+	}
+
+	return &config, nil
+}
+
+// password is read on every use, so the rotated password file is picked up
+func (c *KeystoreConfig) password() (string, error) {
+	if c.PasswordFile == "" {
+		return c.Password, nil
+	}
+
+	raw, err := os.ReadFile(c.PasswordFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the keystore password file")
+	}
+
+	return strings.TrimSpace(string(raw)), nil
+}
+
+func (c *VaultConfig) validate() error {
+	if c.AuthMount == "" {
+		c.AuthMount = c.Auth
+	}
+
+	switch c.Auth {
+	case AuthToken:
+		if c.Token == "" {
+			return errors.New("token is required for the token auth method")
+		}
+	case AuthAppRole:
+		if c.RoleID == "" || (c.SecretID == "" && c.SecretIDFile == "") {
+			return errors.New("role_id and either secret_id or secret_id_file are required for the approle auth method")
+		}
+	case AuthKubernetes:
+		if c.Role == "" {
+			return errors.New("role is required for the kubernetes auth method")
+		}
+	default:
+		return errors.From(errors.New("unknown auth method"), logan.F{"auth": c.Auth})
+	}
+
+	if c.RefreshInterval <= 0 {
+		return errors.New("refresh interval must be positive")
+	}
+
+	return nil
+}
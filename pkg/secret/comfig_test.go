@@ -0,0 +1,86 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+func TestKeystoreConfigFrom(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  string
+		want    *KeystoreConfig
+		wantErr bool
+	}{
+		{
+			name:   "no keystore section",
+			config: "vault:\n  address: \"http://127.0.0.1:8200\"\n",
+			want:   nil,
+		},
+		{
+			name:   "keystore with password",
+			config: "keystore:\n  dir: \"./keys\"\n  password: \"secret\"\n",
+			want: &KeystoreConfig{
+				Dir:             "./keys",
+				Password:        "secret",
+				RefreshInterval: defaultRefreshInterval,
+			},
+		},
+		{
+			name:    "keystore without password",
+			config:  "keystore:\n  dir: \"./keys\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "keystore without dir",
+			config:  "keystore:\n  password: \"secret\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "non-positive refresh interval",
+			config:  "keystore:\n  dir: \"./keys\"\n  password: \"secret\"\n  refresh_interval: \"-1s\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tc.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := KeystoreConfigFrom(kv.NewViperFile(path))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			switch {
+			case tc.want == nil && got != nil:
+				t.Fatalf("expected no keystore config, got %+v", got)
+			case tc.want != nil && (got == nil || *got != *tc.want):
+				t.Fatalf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestKeystoreConfigFromExample(t *testing.T) {
+	// the example config uses Vault, so the keystore is not configured
+	got, err := KeystoreConfigFrom(kv.NewViperFile("../../config-example.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected no keystore config, got %+v", got)
+	}
+}
@@ -3,42 +3,57 @@ package secret
 import (
 	"crypto/ecdsa"
 	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
-type EVMSecrets map[string]*ecdsa.PrivateKey
+// EVMSecrets maps the chains to the pools of the withdrawal signer keys
+type EVMSecrets map[string][]*ecdsa.PrivateKey
 
 func evmSecretsFromMap(data map[string]interface{}) (*EVMSecrets, error) {
 	result := make(EVMSecrets)
 	for k, v := range data {
-		strValue, ok := v.(string)
-		if !ok {
-			return nil, errors.Errorf("invalid private key for the chain: %s", k)
-		}
-
-		pk, err := crypto.HexToECDSA(strValue)
+		rawKeys, err := keyList(v)
 		if err != nil {
-			return nil, errors.Wrap(err, fmt.Sprintf("failed to parse private key for the chain: %s", k))
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid private keys for the chain: %s", k))
 		}
 
-		result[k] = pk
+		for _, rawKey := range rawKeys {
+			pk, err := crypto.HexToECDSA(rawKey)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to parse private key for the chain: %s", k))
+			}
+
+			result[k] = append(result[k], pk)
+		}
 	}
 
 	return &result, nil
 }
 
-func (e *EVMSecrets) PublicKey(chain string) common.Address {
-	if (*e)[chain] == nil {
+// Signers returns the addresses of the chain signer keys
+func (e *EVMSecrets) Signers(chain string) []common.Address {
+	if len((*e)[chain]) == 0 {
 		panic(errors.Wrap(ErrUninitializedPrivateKey, fmt.Sprintf("evm private key is empty for the chain: %s", chain)))
 	}
-	return crypto.PubkeyToAddress((*e)[chain].PublicKey)
+
+	signers := make([]common.Address, len((*e)[chain]))
+	for i, key := range (*e)[chain] {
+		signers[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	return signers
 }
 
-func (e *EVMSecrets) PrivateKey(chain string) *ecdsa.PrivateKey {
-	if (*e)[chain] == nil {
-		panic(errors.Wrap(ErrUninitializedPrivateKey, fmt.Sprintf("evm private key is empty for the chain: %s", chain)))
+// PrivateKey returns the chain signer key of the address
+func (e *EVMSecrets) PrivateKey(chain string, signer common.Address) (*ecdsa.PrivateKey, error) {
+	for _, key := range (*e)[chain] {
+		if crypto.PubkeyToAddress(key.PublicKey) == signer {
+			return key, nil
+		}
 	}
-	return (*e)[chain]
+
+	return nil, errors.Wrap(ErrUnknownSigner, fmt.Sprintf("evm signer %s is not found for the chain: %s", signer, chain))
 }
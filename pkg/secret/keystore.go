@@ -0,0 +1,351 @@
+package secret
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	vaultapi "github.com/hashicorp/vault/api"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	keystoreVersion     = 3
+	keystoreFilePerm    = 0600
+	keystoreDirPerm     = 0700
+	keystoreBouncerFile = bouncerKey + ".json"
+)
+
+// encryptedKey is the keystore file of the non-EVM key, the key is encrypted the same way as the geth keystore
+// keys are, the plain key is in the same format as in the Vault secret
+type encryptedKey struct {
+	// Account is the NEAR account the key is an access key of
+	Account string              `json:"account,omitempty"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	Version int                 `json:"version"`
+}
+
+// localKeystore is the Vault replacement for the local development and the air-gapped rigs, the keys are kept
+// in the encrypted files of the directory:
+//
+//	evm/<chain>/*    the geth keystore files of the chain signer keys
+//	solana/*.json    the base58-encoded private keys
+//	near/*.json      the base58-encoded private keys with their accounts
+//	rarimo/*.json    the hex-encoded private keys
+//	bouncer.json     the hex-encoded bouncer private key
+type localKeystore struct {
+	log *logan.Entry
+	cfg *KeystoreConfig
+
+	mu     sync.RWMutex
+	secret *Secret
+}
+
+func (k *localKeystore) Secret() *Secret {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.secret
+}
+
+// Client returns nil, as there is no Vault server behind the keystore
+func (k *localKeystore) Client() *vaultapi.Client {
+	return nil
+}
+
+func (k *localKeystore) RefreshInterval() time.Duration {
+	return k.cfg.RefreshInterval
+}
+
+func (k *localKeystore) Refresh(_ context.Context) error {
+	if err := k.loadSecret(); err != nil {
+		return errors.Wrap(err, "failed to reload the keystore")
+	}
+
+	return nil
+}
+
+// loadSecret decrypts the keystore files and replaces the current secret only if all of them are valid
+func (k *localKeystore) loadSecret() error {
+	password, err := k.cfg.password()
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string]interface{})
+
+	chains, err := subdirs(filepath.Join(k.cfg.Dir, evmKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to list the evm chains")
+	}
+	if len(chains) != 0 {
+		evm := make(map[string]interface{}, len(chains))
+		for _, chain := range chains {
+			keys, err := readEVMKeys(filepath.Join(k.cfg.Dir, evmKey, chain), password)
+			if err != nil {
+				return errors.Wrap(err, "failed to read the evm keys", logan.F{"chain": chain})
+			}
+			if len(keys) != 0 {
+				evm[chain] = keys
+			}
+		}
+		if len(evm) != 0 {
+			data[evmKey] = evm
+		}
+	}
+
+	for _, set := range []string{solanaKey, rarimoKey, nearKey} {
+		keys, err := readEncryptedKeys(filepath.Join(k.cfg.Dir, set), password)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the keys", logan.F{"set": set})
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			values[i] = key.value
+			if set == nearKey {
+				values[i] = map[string]interface{}{nearPrivateKeyKey: key.value, nearAddressKey: key.account}
+			}
+		}
+		data[set] = values
+	}
+
+	bouncer, err := readEncryptedKey(filepath.Join(k.cfg.Dir, keystoreBouncerFile), password)
+	switch {
+	case os.IsNotExist(errors.Cause(err)):
+	case err != nil:
+		return errors.Wrap(err, "failed to read the bouncer key")
+	default:
+		data[bouncerKey] = bouncer.value
+	}
+
+	secret, err := secretFromData(data, k.log)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.secret = secret
+	k.mu.Unlock()
+
+	k.log.WithField("dir", k.cfg.Dir).Info("[Keystore] Successfully loaded secret storage")
+	return nil
+}
+
+// ImportKey encrypts the key into the keystore and returns the path of the created file. The set is one of the
+// secret keys: evm, solana, near, rarimo or bouncer, the key is in the same format as in the Vault secret and the
+// name is the chain of the EVM key or the account of the NEAR one.
+func ImportKey(cfg KeystoreConfig, set, name, key string) (string, error) {
+	password, err := cfg.password()
+	if err != nil {
+		return "", err
+	}
+
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if cfg.LightKDF {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+
+	var path string
+	switch set {
+	case evmKey:
+		if name == "" {
+			return "", errors.New("chain name is required for the evm key")
+		}
+
+		pk, err := crypto.HexToECDSA(key)
+		if err != nil {
+			return "", errors.Wrap(err, "expected a hex-encoded evm private key")
+		}
+
+		account, err := keystore.NewKeyStore(filepath.Join(cfg.Dir, evmKey, name), scryptN, scryptP).
+			ImportECDSA(pk, password)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to import the evm key")
+		}
+
+		return account.URL.Path, nil
+	case solanaKey:
+		keys, err := solanaSecretsFromValue(key)
+		if err != nil {
+			return "", err
+		}
+
+		path = filepath.Join(cfg.Dir, solanaKey, keys.Signers()[0].String()+".json")
+	case nearKey:
+		if name == "" {
+			return "", errors.New("account is required for the near key")
+		}
+
+		if _, err = nearSecretsFromValue(map[string]interface{}{nearPrivateKeyKey: key, nearAddressKey: name}); err != nil {
+			return "", err
+		}
+
+		path = filepath.Join(cfg.Dir, nearKey, name+".json")
+	case rarimoKey:
+		keys, err := rarimoSecretsFromValue(key)
+		if err != nil {
+			return "", err
+		}
+
+		path = filepath.Join(cfg.Dir, rarimoKey, keys.Signers()[0]+".json")
+	case bouncerKey:
+		if _, err = crypto.HexToECDSA(key); err != nil {
+			return "", errors.Wrap(err, "expected a hex-encoded bouncer private key")
+		}
+
+		path = filepath.Join(cfg.Dir, keystoreBouncerFile)
+	default:
+		return "", errors.From(errors.New("unknown key set"), logan.F{"set": set})
+	}
+
+	encrypted, err := keystore.EncryptDataV3([]byte(key), []byte(password), scryptN, scryptP)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt the key")
+	}
+
+	account := ""
+	if set == nearKey {
+		account = name
+	}
+
+	raw, err := json.Marshal(encryptedKey{Account: account, Crypto: encrypted, Version: keystoreVersion})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the keystore file")
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), keystoreDirPerm); err != nil {
+		return "", errors.Wrap(err, "failed to create the keystore directory")
+	}
+	if _, err = os.Stat(path); err == nil {
+		return "", errors.From(errors.New("key already exists"), logan.F{"path": path})
+	}
+	if err = os.WriteFile(path, raw, keystoreFilePerm); err != nil {
+		return "", errors.Wrap(err, "failed to write the keystore file")
+	}
+
+	return path, nil
+}
+
+type decryptedKey struct {
+	account string
+	value   string
+}
+
+// readEVMKeys decrypts the geth keystore files of the chain, the keys are hex-encoded as in the Vault secret
+func readEVMKeys(dir, password string) ([]interface{}, error) {
+	files, err := keyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]interface{}, 0, len(files))
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the keystore file", logan.F{"path": file})
+		}
+
+		key, err := keystore.DecryptKey(raw, password)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt the keystore file", logan.F{"path": file})
+		}
+
+		keys = append(keys, hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)))
+	}
+
+	return keys, nil
+}
+
+func readEncryptedKeys(dir, password string) ([]decryptedKey, error) {
+	files, err := keyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]decryptedKey, len(files))
+	for i, file := range files {
+		if keys[i], err = readEncryptedKey(file, password); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func readEncryptedKey(file, password string) (decryptedKey, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return decryptedKey{}, errors.Wrap(err, "failed to read the keystore file", logan.F{"path": file})
+	}
+
+	var encrypted encryptedKey
+	if err = json.Unmarshal(raw, &encrypted); err != nil {
+		return decryptedKey{}, errors.Wrap(err, "failed to unmarshal the keystore file", logan.F{"path": file})
+	}
+	if encrypted.Version != keystoreVersion {
+		return decryptedKey{}, errors.From(errors.New("unsupported keystore file version"), logan.F{
+			"path":    file,
+			"version": encrypted.Version,
+		})
+	}
+
+	value, err := keystore.DecryptDataV3(encrypted.Crypto, password)
+	if err != nil {
+		return decryptedKey{}, errors.Wrap(err, "failed to decrypt the keystore file", logan.F{"path": file})
+	}
+
+	return decryptedKey{account: encrypted.Account, value: string(value)}, nil
+}
+
+// keyFiles lists the files of the directory skipping the hidden ones, the missing directory has no files
+func keyFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the keystore directory", logan.F{"path": dir})
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+
+	return files, nil
+}
+
+func subdirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,166 @@
+package secret
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/olegfomenko/solana-go"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+func TestKeystoreImport(t *testing.T) {
+	const (
+		chain       = "Goerli"
+		nearAccount = "relayer.testnet"
+	)
+
+	evmPK, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	solanaPK, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, nearPK, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rarimoPK, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bouncerPK, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := KeystoreConfig{Dir: t.TempDir(), Password: "secret", LightKDF: true}
+
+	imports := []struct {
+		set  string
+		name string
+		key  string
+	}{
+		{set: evmKey, name: chain, key: hex.EncodeToString(crypto.FromECDSA(evmPK))},
+		{set: solanaKey, key: solanaPK.String()},
+		{set: nearKey, name: nearAccount, key: "ed25519:" + solana.PrivateKey(nearPK).String()},
+		{set: rarimoKey, key: hexutil.Encode(crypto.FromECDSA(rarimoPK))},
+		{set: bouncerKey, key: hex.EncodeToString(crypto.FromECDSA(bouncerPK))},
+	}
+	for _, imported := range imports {
+		if _, err := ImportKey(cfg, imported.set, imported.name, imported.key); err != nil {
+			t.Fatalf("failed to import the %s key: %v", imported.set, err)
+		}
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		wrong := cfg
+		wrong.Password = "wrong"
+
+		storage := &localKeystore{cfg: &wrong, log: logan.New()}
+		if err := storage.loadSecret(); err == nil {
+			t.Fatal("expected the keystore not to be decrypted")
+		}
+	})
+
+	storage := &localKeystore{cfg: &cfg, log: logan.New()}
+	if err := storage.loadSecret(); err != nil {
+		t.Fatalf("failed to load the keystore: %v", err)
+	}
+	secret := storage.Secret()
+
+	cases := []struct {
+		name  string
+		check func() error
+	}{
+		{
+			name: "evm",
+			check: func() error {
+				signer := crypto.PubkeyToAddress(evmPK.PublicKey)
+				if signers := secret.EVM().Signers(chain); len(signers) != 1 || signers[0] != signer {
+					return fmt.Errorf("got signers %v, want %s", signers, signer)
+				}
+				key, err := secret.EVM().PrivateKey(chain, signer)
+				if err != nil {
+					return err
+				}
+				if !key.Equal(evmPK) {
+					return fmt.Errorf("got another private key")
+				}
+				return nil
+			},
+		},
+		{
+			name: "solana",
+			check: func() error {
+				signer := solanaPK.PublicKey()
+				if signers := secret.Solana().Signers(); len(signers) != 1 || !signers[0].Equals(signer) {
+					return fmt.Errorf("got signers %v, want %s", signers, signer)
+				}
+				key, err := secret.Solana().PrivateKey(signer)
+				if err != nil {
+					return err
+				}
+				if key.String() != solanaPK.String() {
+					return fmt.Errorf("got another private key")
+				}
+				return nil
+			},
+		},
+		{
+			name: "near",
+			check: func() error {
+				if signers := secret.Near().Signers(); len(signers) != 1 || signers[0] != nearAccount {
+					return fmt.Errorf("got signers %v, want %s", signers, nearAccount)
+				}
+				key, err := secret.Near().PrivateKey(nearAccount)
+				if err != nil {
+					return err
+				}
+				if !key.PrivateKey.Equal(nearPK) {
+					return fmt.Errorf("got another private key")
+				}
+				return nil
+			},
+		},
+		{
+			name: "rarimo",
+			check: func() error {
+				signers := secret.Rarimo().Signers()
+				if len(signers) != 1 {
+					return fmt.Errorf("got signers %v, want one", signers)
+				}
+				key, err := secret.Rarimo().PrivateKey(signers[0])
+				if err != nil {
+					return err
+				}
+				if hexutil.Encode(key.Key) != hexutil.Encode(crypto.FromECDSA(rarimoPK)) {
+					return fmt.Errorf("got another private key")
+				}
+				return nil
+			},
+		},
+		{
+			name: "bouncer",
+			check: func() error {
+				if !secret.Bouncer().Equal(bouncerPK) {
+					return fmt.Errorf("got another private key")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.check(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -3,13 +3,17 @@ package secret
 import (
 	"crypto/ecdsa"
 	"fmt"
-	"github.com/olegfomenko/solana-go"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
 var (
 	ErrUninitializedPrivateKey = errors.New("private key should be initialized")
 	EvmSecretsNotInitialized   = errors.New("evm secrets should be initialized")
+	// ErrUnknownSigner is returned for the signer that is not in the pool, e.g. the rotated out one
+	ErrUnknownSigner = errors.New("unknown signer")
 )
 
 type Secret struct {
@@ -20,30 +24,89 @@ type Secret struct {
 	bouncer *ecdsa.PrivateKey
 }
 
-func newSecret(evm *EVMSecrets, near *NearSecrets, solana solana.PrivateKey, rarimo *RarimoSecrets, bouncer *ecdsa.PrivateKey) (*Secret, error) {
-	if evm == nil {
-		return nil, errors.Wrap(EvmSecretsNotInitialized, "evm secrets are empty")
-	}
-
-	for _, v := range *evm {
-		if v == nil {
-			return nil, errors.Wrap(ErrUninitializedPrivateKey, fmt.Sprintf("evm private key is empty for the chain: %s", v))
+func newSecret(evm *EVMSecrets, near *NearSecrets, solana *SolanaSecrets, rarimo *RarimoSecrets, bouncer *ecdsa.PrivateKey) (*Secret, error) {
+	if evm != nil {
+		for chain, keys := range *evm {
+			if len(keys) == 0 {
+				return nil, errors.Wrap(ErrUninitializedPrivateKey, fmt.Sprintf("evm private key is empty for the chain: %s", chain))
+			}
 		}
 	}
 
-	if bouncer == nil {
-		panic(errors.Wrap(ErrUninitializedPrivateKey, "bouncer private key is empty"))
-	}
-
 	return &Secret{
 		evm,
 		near,
-		NewSolanaSecrets(solana),
+		solana,
 		rarimo,
 		bouncer,
 	}, nil
 }
 
+// secretFromData parses the secret stored either in the Vault KV or in the local keystore, the chain keys are
+// optional, as they are not kept in the secret when the withdrawals are signed by Transit
+func secretFromData(data map[string]interface{}, log *logan.Entry) (*Secret, error) {
+	var (
+		evm *EVMSecrets
+		err error
+	)
+	if value, ok := data[evmKey]; ok {
+		rawEVM, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("expected evm secrets to be a map of the chain keys")
+		}
+
+		if evm, err = evmSecretsFromMap(rawEVM); err != nil {
+			return nil, errors.Wrap(err, "failed to parse evm secrets")
+		}
+		log.WithField("chains", len(*evm)).Info("EVM private keys found")
+	}
+
+	var near *NearSecrets
+	if value, ok := data[nearKey]; ok {
+		if near, err = nearSecretsFromValue(value); err != nil {
+			return nil, errors.Wrap(err, "failed to parse near secrets")
+		}
+		log.WithField("keys", len(near.keys)).Info("Near secrets key found")
+	}
+
+	var sol *SolanaSecrets
+	if value, ok := data[solanaKey]; ok {
+		if sol, err = solanaSecretsFromValue(value); err != nil {
+			return nil, errors.Wrap(err, "failed to parse solana secrets")
+		}
+		log.WithField("keys", len(sol.keys)).Info("Solana private key found")
+	}
+
+	var rarimo *RarimoSecrets
+	if value, ok := data[rarimoKey]; ok {
+		if rarimo, err = rarimoSecretsFromValue(value); err != nil {
+			return nil, errors.Wrap(err, "expected a hex-encoded rarimo private key")
+		}
+		log.WithField("keys", len(rarimo.keys)).Info("Rarimo private key found")
+	}
+
+	// the bouncer key is only needed to issue the admin tokens
+	var bouncer *ecdsa.PrivateKey
+	if value, ok := data[bouncerKey]; ok {
+		rawBouncer, ok := value.(string)
+		if !ok {
+			return nil, errors.New("expected a hex-encoded bouncer private key")
+		}
+
+		if bouncer, err = crypto.HexToECDSA(rawBouncer); err != nil {
+			return nil, errors.Wrap(err, "expected a hex-encoded bouncer private key")
+		}
+		log.Info("Bouncer private key found")
+	}
+
+	secret, err := newSecret(evm, near, sol, rarimo, bouncer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create secret")
+	}
+
+	return secret, nil
+}
+
 func (s *Secret) EVM() *EVMSecrets {
 	return s.evm
 }
@@ -63,3 +126,28 @@ func (s *Secret) Rarimo() *RarimoSecrets {
 func (s *Secret) Bouncer() *ecdsa.PrivateKey {
 	return s.bouncer
 }
+
+// keyList accepts either a single key or the pool of keys
+func keyList(data interface{}) ([]string, error) {
+	switch value := data.(type) {
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		if len(value) == 0 {
+			return nil, errors.New("key list is empty")
+		}
+
+		keys := make([]string, len(value))
+		for i, key := range value {
+			strKey, ok := key.(string)
+			if !ok {
+				return nil, errors.New("key is not a string")
+			}
+			keys[i] = strKey
+		}
+
+		return keys, nil
+	default:
+		return nil, errors.New("expected a key or a list of keys")
+	}
+}
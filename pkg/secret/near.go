@@ -6,35 +6,74 @@ import (
 )
 
 type NearSecrets struct {
+	keys []nearSignerKey
+}
+
+type nearSignerKey struct {
 	key     common.KeyPair
 	address string
 }
 
-func nearSecretsFromMap(data map[string]interface{}) (*NearSecrets, error) {
-	result := NearSecrets{}
-	var err error
-	strPk, ok := data[nearPrivateKeyKey].(string)
-	if !ok {
-		return nil, errors.New("invalid private key for the near chain")
+// nearSecretsFromValue parses either a single account or the list of them
+func nearSecretsFromValue(data interface{}) (*NearSecrets, error) {
+	var rawKeys []interface{}
+	switch value := data.(type) {
+	case map[string]interface{}:
+		rawKeys = []interface{}{value}
+	case []interface{}:
+		rawKeys = value
+	default:
+		return nil, errors.New("invalid near secrets")
 	}
 
-	result.key, err = common.NewBase58KeyPair(strPk)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse private key for the near chain")
+	result := NearSecrets{keys: make([]nearSignerKey, len(rawKeys))}
+	for i, rawKey := range rawKeys {
+		data, ok := rawKey.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("invalid near secrets")
+		}
+
+		strPk, ok := data[nearPrivateKeyKey].(string)
+		if !ok {
+			return nil, errors.New("invalid private key for the near chain")
+		}
+
+		var err error
+		result.keys[i].key, err = common.NewBase58KeyPair(strPk)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse private key for the near chain")
+		}
+
+		result.keys[i].address, ok = data[nearAddressKey].(string)
+		if !ok {
+			return nil, errors.New("invalid address for the near chain")
+		}
 	}
 
-	result.address, ok = data[nearAddressKey].(string)
-	if !ok {
-		return nil, errors.New("invalid address for the near chain")
+	if len(result.keys) == 0 {
+		return nil, errors.New("near secrets are empty")
 	}
 
 	return &result, nil
 }
 
-func (s *NearSecrets) PublicKey() string {
-	return s.address
+// Signers returns the accounts of the signer keys
+func (s *NearSecrets) Signers() []string {
+	signers := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		signers[i] = key.address
+	}
+
+	return signers
 }
 
-func (s *NearSecrets) PrivateKey() common.KeyPair {
-	return s.key
+// PrivateKey returns the key of the signer account
+func (s *NearSecrets) PrivateKey(signer string) (common.KeyPair, error) {
+	for _, key := range s.keys {
+		if key.address == signer {
+			return key.key, nil
+		}
+	}
+
+	return common.KeyPair{}, errors.Wrap(ErrUnknownSigner, "near signer "+signer+" is not found")
 }
@@ -8,26 +8,54 @@ import (
 )
 
 type RarimoSecrets struct {
+	keys []rarimoSignerKey
+}
+
+type rarimoSignerKey struct {
 	key     *secp256k1.PrivKey
 	address string
 }
 
-func rarimoSecretsFromMap(data string) (*RarimoSecrets, error) {
-	result := RarimoSecrets{}
-	var err error
-	
-	result.key = &secp256k1.PrivKey{Key: hexutil.MustDecode(data)}
-	result.address, err = bech32.ConvertAndEncode("rarimo", result.key.PubKey().Address().Bytes())
+func rarimoSecretsFromValue(data interface{}) (*RarimoSecrets, error) {
+	rawKeys, err := keyList(data)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to convert rarimo address")
+		return nil, errors.Wrap(err, "invalid rarimo private keys")
+	}
+
+	result := RarimoSecrets{keys: make([]rarimoSignerKey, len(rawKeys))}
+	for i, rawKey := range rawKeys {
+		rawBytes, err := hexutil.Decode(rawKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode rarimo private key")
+		}
+
+		result.keys[i].key = &secp256k1.PrivKey{Key: rawBytes}
+		result.keys[i].address, err = bech32.ConvertAndEncode("rarimo", result.keys[i].key.PubKey().Address().Bytes())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert rarimo address")
+		}
 	}
+
 	return &result, nil
 }
 
-func (s *RarimoSecrets) PublicKey() string {
-	return s.address
+// Signers returns the addresses of the signer keys
+func (s *RarimoSecrets) Signers() []string {
+	signers := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		signers[i] = key.address
+	}
+
+	return signers
 }
 
-func (s *RarimoSecrets) PrivateKey() *secp256k1.PrivKey {
-	return s.key
+// PrivateKey returns the key of the signer address
+func (s *RarimoSecrets) PrivateKey(signer string) (*secp256k1.PrivKey, error) {
+	for _, key := range s.keys {
+		if key.address == signer {
+			return key.key, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrUnknownSigner, "rarimo signer "+signer+" is not found")
 }
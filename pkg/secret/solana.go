@@ -1,19 +1,52 @@
 package secret
 
-import "github.com/olegfomenko/solana-go"
+import (
+	"github.com/olegfomenko/solana-go"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
 
 type SolanaSecrets struct {
-	key solana.PrivateKey
+	keys []solana.PrivateKey
 }
 
-func NewSolanaSecrets(key solana.PrivateKey) *SolanaSecrets {
-	return &SolanaSecrets{key: key}
+func NewSolanaSecrets(keys ...solana.PrivateKey) *SolanaSecrets {
+	return &SolanaSecrets{keys: keys}
 }
 
-func (s *SolanaSecrets) PublicKey() solana.PublicKey {
-	return s.key.PublicKey()
+func solanaSecretsFromValue(data interface{}) (*SolanaSecrets, error) {
+	rawKeys, err := keyList(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid solana private keys")
+	}
+
+	keys := make([]solana.PrivateKey, len(rawKeys))
+	for i, rawKey := range rawKeys {
+		keys[i], err = solana.PrivateKeyFromBase58(rawKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "valid base58-encoded solana private key expected")
+		}
+	}
+
+	return NewSolanaSecrets(keys...), nil
+}
+
+// Signers returns the public keys of the signer keys
+func (s *SolanaSecrets) Signers() []solana.PublicKey {
+	signers := make([]solana.PublicKey, len(s.keys))
+	for i, key := range s.keys {
+		signers[i] = key.PublicKey()
+	}
+
+	return signers
 }
 
-func (s *SolanaSecrets) PrivateKey() solana.PrivateKey {
-	return s.key
+// PrivateKey returns the signer key of the public key
+func (s *SolanaSecrets) PrivateKey(signer solana.PublicKey) (solana.PrivateKey, error) {
+	for _, key := range s.keys {
+		if key.PublicKey().Equals(signer) {
+			return key, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrUnknownSigner, "solana signer "+signer.String()+" is not found")
 }
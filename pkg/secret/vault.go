@@ -2,9 +2,10 @@ package secret
 
 import (
 	"context"
-	"github.com/ethereum/go-ethereum/crypto"
+	"sync"
+	"time"
+
 	vaultapi "github.com/hashicorp/vault/api"
-	"github.com/olegfomenko/solana-go"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
@@ -21,62 +22,71 @@ const (
 
 type Vault interface {
 	Secret() *Secret
+	// Client returns the authenticated Vault client, e.g. for the Transit signer
+	Client() *vaultapi.Client
+	// Refresh keeps the token alive and reloads the secret, so the rotated keys take effect without a restart
+	Refresh(ctx context.Context) error
+	RefreshInterval() time.Duration
 }
 
 type vault struct {
-	log      *logan.Entry
-	secret   *Secret
-	kvSecret *vaultapi.KVSecret
-	client   *vaultapi.KVv2
-	cfg      *VaultConfig
+	log    *logan.Entry
+	api    *vaultapi.Client
+	client *vaultapi.KVv2
+	cfg    *VaultConfig
+
+	mu     sync.RWMutex
+	secret *Secret
 }
 
 func (v *vault) Secret() *Secret {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
 	return v.secret
 }
 
-func (v *vault) loadSecret() error {
-	var err error
-	v.kvSecret, err = v.client.Get(context.Background(), v.cfg.Secret)
-	if err != nil {
-		return errors.Wrap(err, "failed to get secret data")
-	}
+func (v *vault) Client() *vaultapi.Client {
+	return v.api
+}
 
-	evm, err := evmSecretsFromMap(v.kvSecret.Data[evmKey].(map[string]interface{}))
-	if err != nil {
-		return errors.Wrap(err, "failed to parse evm secrets")
-	}
+func (v *vault) RefreshInterval() time.Duration {
+	return v.cfg.RefreshInterval
+}
 
-	near, err := nearSecretsFromMap(v.kvSecret.Data[nearKey].(map[string]interface{}))
-	if err != nil {
-		return errors.Wrap(err, "failed to parse near secrets")
+func (v *vault) Refresh(ctx context.Context) error {
+	if err := v.keepToken(ctx); err != nil {
+		return errors.Wrap(err, "failed to keep the token alive")
 	}
-	v.log.Info("[Vault] Near secrets key found")
 
-	sol, err := solana.PrivateKeyFromBase58(v.kvSecret.Data[solanaKey].(string))
-	if err != nil {
-		return errors.Wrap(err, "valid base58-encoded solana private key expected")
+	if err := v.loadSecret(ctx); err != nil {
+		return errors.Wrap(err, "failed to reload the secret")
 	}
-	v.log.Info("[Vault] Solana private key found")
 
-	rarimo, err := rarimoSecretsFromMap(v.kvSecret.Data[rarimoKey].(string))
-	if err != nil {
-		return errors.Wrap(err, "expected a hex-encoded rarimo private key")
-	}
-	v.log.Info("[Vault] Rarimo private key found")
+	return nil
+}
 
-	bouncer, err := crypto.HexToECDSA(v.kvSecret.Data[bouncerKey].(string))
+// loadSecret reads the secret and replaces the current one only if the whole secret is valid
+func (v *vault) loadSecret(ctx context.Context) error {
+	kvSecret, err := v.client.Get(ctx, v.cfg.Secret)
 	if err != nil {
-		return errors.Wrap(err, "expected a hex-encoded bouncer private key")
+		return errors.Wrap(err, "failed to get secret data")
 	}
-	v.log.Info("[Vault] Bouncer private key found")
 
-	v.secret, err = newSecret(evm, near, sol, rarimo, bouncer)
+	secret, err := secretFromData(kvSecret.Data, v.log)
 	if err != nil {
-		return errors.Wrap(err, "failed to create secret")
+		return err
 	}
 
-	v.log.Info("[Vault] Successfully initiated secret storage")
+	v.mu.Lock()
+	v.secret = secret
+	v.mu.Unlock()
+
+	log := v.log
+	if kvSecret.VersionMetadata != nil {
+		log = log.WithField("version", kvSecret.VersionMetadata.Version)
+	}
+	log.Info("[Vault] Successfully loaded secret storage")
 
 	return nil
 }
@@ -0,0 +1,21 @@
+/*
+ * GENERATED. Do not modify. Your changes might be overwritten!
+ */
+
+package resources
+
+type ChainHealth struct {
+	// Whether the chain has at least one healthy endpoint
+	Available bool `json:"available"`
+	// States of the circuit breakers of the chain endpoints as last reported by the relayer mapped by the endpoint index, one of `closed`, `half-open` or `open`
+	CircuitBreakers map[string]string `json:"circuit_breakers,omitempty"`
+	// Drained signers of the chain pool mapped to the drain reasons, `manual` for the admin drains, `low_funds` for the automatic ones
+	DrainedSigners map[string]string `json:"drained_signers,omitempty"`
+	Name           string            `json:"name"`
+	// Whether relaying to the chain is paused
+	Paused bool `json:"paused"`
+	// Reason the chain was paused with, `manual` for the admin pauses, `low_funds` for the automatic ones
+	PauseReason *string `json:"pause_reason,omitempty"`
+	// Time the chain became unavailable at
+	UnavailableSince *string `json:"unavailable_since,omitempty"`
+}
@@ -0,0 +1,31 @@
+/*
+ * GENERATED. Do not modify. Your changes might be overwritten!
+ */
+
+package resources
+
+type Health struct {
+	Key
+	Attributes HealthAttributes `json:"attributes"`
+}
+type HealthResponse struct {
+	Data     Health   `json:"data"`
+	Included Included `json:"included"`
+}
+
+type HealthListResponse struct {
+	Data     []Health `json:"data"`
+	Included Included `json:"included"`
+	Links    *Links   `json:"links"`
+}
+
+// MustHealth - returns Health from include collection.
+// if entry with specified key does not exist - returns nil
+// if entry with specified key exists but type or ID mismatches - panics
+func (c *Included) MustHealth(key Key) *Health {
+	var health Health
+	if c.tryFindEntry(key, &health) {
+		return &health
+	}
+	return nil
+}
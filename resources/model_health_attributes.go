@@ -0,0 +1,12 @@
+/*
+ * GENERATED. Do not modify. Your changes might be overwritten!
+ */
+
+package resources
+
+type HealthAttributes struct {
+	Chains []ChainHealth `json:"chains"`
+	// Whether the service dependencies are reachable
+	Healthy bool        `json:"healthy"`
+	Vault   VaultHealth `json:"vault"`
+}
@@ -10,6 +10,7 @@ type ResourceType string
 const (
 	CONFIRMATIONS ResourceType = "confirmations"
 	FEE_ESTIMATES ResourceType = "fee_estimates"
+	HEALTH        ResourceType = "health"
 	RELAY_TASKS   ResourceType = "relay_tasks"
 	TRANSFERS     ResourceType = "transfers"
 )
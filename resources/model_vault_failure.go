@@ -0,0 +1,14 @@
+/*
+ * GENERATED. Do not modify. Your changes might be overwritten!
+ */
+
+package resources
+
+type VaultFailure struct {
+	// Error of the failing refresh
+	Error string `json:"error"`
+	// Time the refresh started failing at
+	FailingSince string `json:"failing_since"`
+	// Host name of the relayer instance
+	Instance string `json:"instance"`
+}
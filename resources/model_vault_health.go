@@ -0,0 +1,12 @@
+/*
+ * GENERATED. Do not modify. Your changes might be overwritten!
+ */
+
+package resources
+
+type VaultHealth struct {
+	// Relayer instances whose Vault refresh is failing, they keep using the last loaded secret meanwhile
+	FailingInstances []VaultFailure `json:"failing_instances,omitempty"`
+	// Whether the last Vault token renewal and secret reload succeeded on every relayer instance
+	Healthy bool `json:"healthy"`
+}